@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/generation"
 	"github.com/jacobbrewer1/goschema/pkg/logging"
 	"github.com/jacobbrewer1/goschema/pkg/migrations"
 )
@@ -21,6 +23,15 @@ type createCmd struct {
 
 	// OutputLocation is the location to write the generated files to.
 	outputLocation string
+
+	// configLocation is the location of the config file.
+	configLocation string
+
+	// environment is the name of the config environment to create the migration for.
+	environment string
+
+	// audit is whether to create the migration of the audit log table.
+	audit bool
 }
 
 func (c *createCmd) Name() string {
@@ -34,25 +45,49 @@ func (c *createCmd) Synopsis() string {
 func (c *createCmd) Usage() string {
 	return `create:
   Create a new migration.
+
+  With -audit, the migration creates the audit_log table that the generated models record their writes in when
+  audit is set in the config.
 `
 }
 
 func (c *createCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&c.name, "name", "", "The name of the migration to create.")
 	f.StringVar(&c.outputLocation, "out", ".", "The location to write the generated files to.")
+	f.StringVar(&c.configLocation, "config", "", "The location of the config file. Defaults to the closest "+config.DefaultFile+".")
+	f.StringVar(&c.environment, "env", "", "The config environment to create the migration for. Defaults to the default environment of the config.")
+	f.BoolVar(&c.audit, "audit", false, "Create the migration of the audit log table. The name defaults to create_"+generation.AuditTableName+".")
 }
 
-func (c *createCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+func (c *createCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+	if c.audit && c.name == "" {
+		c.name = "create_" + generation.AuditTableName
+	}
+
 	if c.name == "" {
 		slog.Error("Name is required")
 		return subcommands.ExitUsageError
 	}
 
-	switch c.outputLocation {
-	case "":
+	env, err := loadEnvironment(c.configLocation, c.environment)
+	if err != nil {
+		slog.Error("Error loading environment",
+			slog.String(logging.KeyEnvironment, c.environment),
+			slog.String(logging.KeyError, err.Error()))
+		return subcommands.ExitFailure
+	}
+
+	if env != nil && env.Migrations != "" && !setFlags(f)["out"] {
+		c.outputLocation = env.Migrations
+	}
+
+	switch {
+	case c.outputLocation == "":
 		slog.Error("Output location is required")
 		return subcommands.ExitUsageError
-	case ".":
+	case filepath.IsAbs(c.outputLocation):
+		// The output location is already absolute, e.g. the migrations of an environment
+	case c.outputLocation == ".":
 		// Get the directory that called the command
 		dir, err := os.Getwd()
 		if err != nil {
@@ -103,7 +138,12 @@ func (c *createCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...any) subcom
 		return subcommands.ExitFailure
 	}
 
-	if err := createFile(upAbs); err != nil {
+	var upSQL, downSQL string
+	if c.audit {
+		upSQL, downSQL = generation.AuditUpSQL, generation.AuditDownSQL
+	}
+
+	if err := createFile(upAbs, upSQL); err != nil {
 		slog.Error("Error creating file",
 			slog.String(logging.KeyPath, upAbs),
 			slog.String(logging.KeyError, err.Error()),
@@ -114,7 +154,7 @@ func (c *createCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...any) subcom
 	slog.Info("Up migration created",
 		slog.String(logging.KeyPath, upAbs))
 
-	if err := createFile(downAbs); err != nil {
+	if err := createFile(downAbs, downSQL); err != nil {
 		slog.Error("Error creating file",
 			slog.String(logging.KeyPath, downAbs),
 			slog.String(logging.KeyError, err.Error()),
@@ -128,7 +168,8 @@ func (c *createCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...any) subcom
 	return subcommands.ExitSuccess
 }
 
-func createFile(name string) error {
+// createFile creates the file with the given contents, along with its directory
+func createFile(name, contents string) error {
 	// Create the path if it does not exist.
 	dir := filepath.Dir(name)
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
@@ -140,5 +181,10 @@ func createFile(name string) error {
 		return fmt.Errorf("error creating file: %w", err)
 	}
 
+	if _, err := f.WriteString(contents); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
 	return f.Close()
 }
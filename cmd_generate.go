@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"embed"
+	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
 	"path/filepath"
 
 	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
 	"github.com/jacobbrewer1/goschema/pkg/generation"
 	"github.com/jacobbrewer1/goschema/pkg/logging"
 )
@@ -25,11 +29,23 @@ type generateCmd struct {
 	// sqlLocation is the location of the SQL files to use.
 	sqlLocation string
 
+	// migrationLocation is the location of the migrations to replay instead of the SQL files.
+	migrationLocation string
+
 	// fileExtensionPrefix is the prefix to add to the generated file extension.
 	fileExtensionPrefix string
 
+	// packageName is the name of the generated package.
+	packageName string
+
 	// defaultTemplates is whether to use the binary templates.
 	defaultTemplates bool
+
+	// configLocation is the location of the config file.
+	configLocation string
+
+	// target is the name of the config target to generate.
+	target string
 }
 
 func (g *generateCmd) Name() string {
@@ -43,6 +59,9 @@ func (g *generateCmd) Synopsis() string {
 func (g *generateCmd) Usage() string {
 	return `generate:
   Generate GO types from a MySQL schema.
+
+  When a goschema.yaml file is found in the working directory or one of its parents, every target in the file is
+  generated. Flags override the values of the targets.
 `
 }
 
@@ -50,29 +69,31 @@ func (g *generateCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&g.templatesLocation, "templates", "./templates/*.tmpl", "The location of the templates to use.")
 	f.StringVar(&g.outputLocation, "out", ".", "The location to write the generated files to.")
 	f.StringVar(&g.sqlLocation, "sql", "./schemas/*.sql", "The location of the SQL files to use.")
+	f.StringVar(&g.migrationLocation, "migrations", "", "The location of the migrations to build the schema from. Overrides -sql when set.")
 	f.StringVar(&g.fileExtensionPrefix, "extension", "xo", "The prefix to add to the generated file extension.")
+	f.StringVar(&g.packageName, "package", "", "The name of the generated package. Defaults to the name of the output directory.")
 	f.BoolVar(&g.defaultTemplates, "default", true, "Whether to use the default templates.")
+	f.StringVar(&g.configLocation, "config", "", "The location of the config file. Defaults to the closest "+config.DefaultFile+".")
+	f.StringVar(&g.target, "target", "", "The config target to generate. Defaults to every target.")
 }
 
-func (g *generateCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...any) subcommands.ExitStatus {
-	var err error
-	g.outputLocation, err = filepath.Abs(g.outputLocation)
+func (g *generateCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+	cfg, err := loadConfig(g.configLocation)
 	if err != nil {
-		slog.Error("Error getting absolute path",
-			slog.String(logging.KeyOutputLoc, g.outputLocation),
+		slog.Error("Error loading config",
+			slog.String(logging.KeyConfigLoc, g.configLocation),
 			slog.String(logging.KeyError, err.Error()),
 		)
 		return subcommands.ExitFailure
 	}
 
-	// Load the SQL file locations as abs.
-	g.sqlLocation, err = filepath.Abs(g.sqlLocation)
+	targets, err := g.targets(cfg, setFlags(f))
 	if err != nil {
-		slog.Error("Error getting absolute path",
-			slog.String(logging.KeySqlLoc, g.sqlLocation),
+		slog.Error("Error getting targets",
+			slog.String(logging.KeyTarget, g.target),
 			slog.String(logging.KeyError, err.Error()),
 		)
-		return subcommands.ExitFailure
+		return subcommands.ExitUsageError
 	}
 
 	if err := generation.GoimportsInstallIfNeeded(); err != nil {
@@ -82,47 +103,124 @@ func (g *generateCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...any) subc
 		return subcommands.ExitFailure
 	}
 
-	tables, err := generation.LoadSQL(g.sqlLocation)
-	if err != nil {
-		slog.Error("Error loading SQL",
-			slog.String(logging.KeyTmplLoc, g.templatesLocation),
-			slog.String(logging.KeySqlLoc, g.outputLocation),
-			slog.String(logging.KeyError, err.Error()),
-		)
-		return subcommands.ExitFailure
-	} else if len(tables) == 0 {
-		slog.Info("No tables found", slog.String(logging.KeySqlLoc, g.sqlLocation))
-		return subcommands.ExitFailure
-	}
-
-	if g.defaultTemplates {
-		err = generation.RenderWithTemplates(defaultTemplates, tables, g.outputLocation, g.fileExtensionPrefix)
-		if err != nil {
-			slog.Error("Error rendering default templates",
-				slog.String(logging.KeyOutputLoc, g.outputLocation),
+	for _, t := range targets {
+		if err := generateTarget(cfg.ForTarget(t), t); err != nil {
+			slog.Error("Error generating target",
+				slog.String(logging.KeyTarget, t.Name),
 				slog.String(logging.KeyError, err.Error()),
 			)
 			return subcommands.ExitFailure
 		}
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// targets returns the targets to generate. The config targets are used when there are any, with the flags that
+// were set overriding their values. Otherwise, a single target is built from the flags.
+func (g *generateCmd) targets(cfg *config.Config, set map[string]bool) ([]*config.Target, error) {
+	var targets []*config.Target
+	switch {
+	case g.target != "":
+		t, err := cfg.Target(g.target)
+		if err != nil {
+			return nil, err
+		}
+		targets = []*config.Target{t}
+	case len(cfg.Targets) > 0:
+		targets = cfg.AllTargets()
+	default:
+		set = map[string]bool{"sql": true, "migrations": true, "out": true, "extension": true, "package": true, "default": true}
+		targets = []*config.Target{{Name: "default"}}
+	}
+
+	ret := make([]*config.Target, 0, len(targets))
+	for _, t := range targets {
+		t, err := g.override(*t, set)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, t)
+	}
+
+	return ret, nil
+}
+
+// override returns the target with the values of the flags that were set
+func (g *generateCmd) override(t config.Target, set map[string]bool) (*config.Target, error) {
+	var err error
+	if set["sql"] {
+		if t.SQL, err = filepath.Abs(g.sqlLocation); err != nil {
+			return nil, fmt.Errorf("error getting absolute path: %w", err)
+		}
+		if !set["migrations"] {
+			t.Migrations = ""
+		}
+	}
+	if set["migrations"] && g.migrationLocation != "" {
+		if t.Migrations, err = filepath.Abs(g.migrationLocation); err != nil {
+			return nil, fmt.Errorf("error getting absolute path: %w", err)
+		}
+	}
+	if set["out"] {
+		if t.Out, err = filepath.Abs(g.outputLocation); err != nil {
+			return nil, fmt.Errorf("error getting absolute path: %w", err)
+		}
+	}
+	if set["extension"] {
+		t.Extension = g.fileExtensionPrefix
+	}
+	if set["package"] {
+		t.Package = g.packageName
+	}
+	if set["default"] || set["templates"] {
+		t.Templates = ""
+		if !g.defaultTemplates {
+			t.Templates = g.templatesLocation
+		}
+	}
+
+	return &t, nil
+}
+
+// generateTarget generates the models of the target
+func generateTarget(cfg *config.Config, t *config.Target) error {
+	var (
+		tables []*entities.Table
+		err    error
+	)
+	if t.Migrations != "" {
+		tables, err = generation.LoadMigrations(t.Migrations)
+		if err != nil {
+			return fmt.Errorf("error loading migrations from %s: %w", t.Migrations, err)
+		}
 	} else {
-		err = generation.RenderTemplates(tables, g.templatesLocation, g.outputLocation, g.fileExtensionPrefix)
+		tables, err = generation.LoadSQL(t.SQL)
 		if err != nil {
-			slog.Error("Error rendering templates",
-				slog.String(logging.KeyTmplLoc, g.templatesLocation),
-				slog.String(logging.KeyOutputLoc, g.outputLocation),
-				slog.String(logging.KeyError, err.Error()),
-			)
-			return subcommands.ExitFailure
+			return fmt.Errorf("error loading SQL from %s: %w", t.SQL, err)
 		}
 	}
+	if len(tables) == 0 {
+		return errors.New("no tables found")
+	}
 
-	if err := generation.FmtTemplates(g.outputLocation); err != nil {
-		slog.Error("Error formatting templates",
-			slog.String(logging.KeyOutputLoc, g.outputLocation),
-			slog.String(logging.KeyError, err.Error()),
-		)
-		return subcommands.ExitFailure
+	if t.Templates == "" {
+		err = generation.RenderWithTemplates(cfg, defaultTemplates, tables, t.Out, t.Package, t.Extension)
+	} else {
+		err = generation.RenderTemplates(cfg, tables, t.Templates, t.Out, t.Package, t.Extension)
+	}
+	if err != nil {
+		return fmt.Errorf("error rendering templates to %s: %w", t.Out, err)
 	}
 
-	return subcommands.ExitSuccess
+	if err := generation.FmtTemplates(t.Out); err != nil {
+		return fmt.Errorf("error formatting templates in %s: %w", t.Out, err)
+	}
+
+	slog.Info("Generated target",
+		slog.String(logging.KeyTarget, t.Name),
+		slog.String(logging.KeyOutputLoc, t.Out),
+	)
+
+	return nil
 }
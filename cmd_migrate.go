@@ -4,10 +4,10 @@ import (
 	"context"
 	"flag"
 	"log/slog"
-	"os"
 	"path/filepath"
 
 	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/goschema/pkg/config"
 	"github.com/jacobbrewer1/goschema/pkg/logging"
 	"github.com/jacobbrewer1/goschema/pkg/migrations"
 )
@@ -24,6 +24,12 @@ type migrateCmd struct {
 
 	// steps is the number of steps to migrate.
 	steps int
+
+	// configLocation is the location of the config file.
+	configLocation string
+
+	// environment is the name of the config environment to migrate.
+	environment string
 }
 
 func (m *migrateCmd) Name() string {
@@ -45,9 +51,11 @@ func (m *migrateCmd) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&m.down, "down", false, "Migrate down.")
 	f.StringVar(&m.migrationLocation, "loc", ".", "The location of the migrations.")
 	f.IntVar(&m.steps, "steps", 0, "The number of steps to migrate (0 means all).")
+	f.StringVar(&m.configLocation, "config", "", "The location of the config file. Defaults to the closest "+config.DefaultFile+".")
+	f.StringVar(&m.environment, "env", "", "The config environment to migrate. Defaults to the default environment of the config.")
 }
 
-func (m *migrateCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+func (m *migrateCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
 	if m.up && m.down {
 		slog.Error("Cannot migrate up and down at the same time")
 		return subcommands.ExitUsageError
@@ -56,12 +64,18 @@ func (m *migrateCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...any) sub
 		return subcommands.ExitUsageError
 	}
 
-	if e := os.Getenv(migrations.DbEnvVar); e == "" {
-		slog.Error("Database environment variable not set",
-			slog.String(logging.KeyVariable, migrations.DbEnvVar))
+	env, err := loadEnvironment(m.configLocation, m.environment)
+	if err != nil {
+		slog.Error("Error loading environment",
+			slog.String(logging.KeyEnvironment, m.environment),
+			slog.String(logging.KeyError, err.Error()))
 		return subcommands.ExitFailure
 	}
 
+	if env != nil && env.Migrations != "" && !setFlags(f)["loc"] {
+		m.migrationLocation = env.Migrations
+	}
+
 	absPath, err := filepath.Abs(m.migrationLocation)
 	if err != nil {
 		slog.Error("Error getting absolute path",
@@ -69,7 +83,7 @@ func (m *migrateCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...any) sub
 		return subcommands.ExitFailure
 	}
 
-	db, err := migrations.ConnectDB()
+	db, err := connectDB(env)
 	if err != nil {
 		slog.Error("Error connecting to the database",
 			slog.String(logging.KeyError, err.Error()))
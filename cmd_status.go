@@ -4,16 +4,22 @@ import (
 	"context"
 	"flag"
 	"log/slog"
-	"os"
 	"strconv"
 
 	"github.com/google/subcommands"
+	"github.com/jacobbrewer1/goschema/pkg/config"
 	"github.com/jacobbrewer1/goschema/pkg/logging"
 	"github.com/jacobbrewer1/goschema/pkg/migrations"
 	"github.com/pterm/pterm"
 )
 
-type statusCmd struct{}
+type statusCmd struct {
+	// configLocation is the location of the config file.
+	configLocation string
+
+	// environment is the name of the config environment to print the status of.
+	environment string
+}
 
 func (c *statusCmd) Name() string {
 	return "status"
@@ -29,16 +35,21 @@ func (c *statusCmd) Usage() string {
 `
 }
 
-func (c *statusCmd) SetFlags(f *flag.FlagSet) {}
+func (c *statusCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.configLocation, "config", "", "The location of the config file. Defaults to the closest "+config.DefaultFile+".")
+	f.StringVar(&c.environment, "env", "", "The config environment to print the status of. Defaults to the default environment of the config.")
+}
 
 func (c *statusCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...any) subcommands.ExitStatus {
-	if e := os.Getenv(migrations.DbEnvVar); e == "" {
-		slog.Error("Database environment variable not set",
-			slog.String(logging.KeyVariable, migrations.DbEnvVar))
+	env, err := loadEnvironment(c.configLocation, c.environment)
+	if err != nil {
+		slog.Error("Error loading environment",
+			slog.String(logging.KeyEnvironment, c.environment),
+			slog.String(logging.KeyError, err.Error()))
 		return subcommands.ExitFailure
 	}
 
-	db, err := migrations.ConnectDB()
+	db, err := connectDB(env)
 	if err != nil {
 		slog.Error("Error connecting to the database",
 			slog.String(logging.KeyError, err.Error()))
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/migrations"
+	"github.com/jmoiron/sqlx"
+)
+
+// loadConfig loads the config file at the given location. When no location is given, the closest config file to
+// the working directory is used. An empty config is returned if there is none.
+func loadConfig(location string) (*config.Config, error) {
+	if location != "" {
+		return config.Load(location)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := config.Find(wd)
+	if errors.Is(err, config.ErrNotFound) {
+		return new(config.Config), nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return config.Load(file)
+}
+
+// setFlags returns the names of the flags that were set on the command line
+func setFlags(f *flag.FlagSet) map[string]bool {
+	set := make(map[string]bool)
+	f.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+
+	return set
+}
+
+// loadEnvironment returns the migration environment with the given name, or the default environment of the config
+// if no name is given. A nil environment is returned if there is neither.
+func loadEnvironment(configLocation, name string) (*config.Environment, error) {
+	cfg, err := loadConfig(configLocation)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	return cfg.Environment(name)
+}
+
+// connectDB connects to the database of the given environment. The database in the DATABASE_URL environment
+// variable is used if there is no environment.
+func connectDB(env *config.Environment) (*sqlx.DB, error) {
+	if env == nil {
+		if os.Getenv(migrations.DbEnvVar) == "" {
+			return nil, fmt.Errorf("database environment variable %s not set", migrations.DbEnvVar)
+		}
+
+		return migrations.ConnectDB()
+	}
+
+	dsn, err := env.DataSourceName()
+	if err != nil {
+		return nil, fmt.Errorf("environment %s: %w", env.Name, err)
+	}
+
+	return migrations.ConnectDSN(dsn)
+}
@@ -0,0 +1,305 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jacobbrewer1/goschema/pkg/logging"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultFile is the name of the config file that is discovered when no config file is given.
+	DefaultFile = "goschema.yaml"
+
+	// DefaultSoftDeleteColumn is the name of the column that marks soft deleted rows when none is configured.
+	DefaultSoftDeleteColumn = "deleted_at"
+
+	// NoSoftDelete is the soft_delete setting that turns soft deletes off.
+	NoSoftDelete = "-"
+)
+
+var (
+	// ErrInvalidConfig is returned when the config file is not valid.
+	ErrInvalidConfig = errors.New("invalid config")
+
+	// ErrNotFound is returned when no config file is found.
+	ErrNotFound = errors.New("config file not found")
+
+	// ErrUnknownTarget is returned when a target is not in the config.
+	ErrUnknownTarget = errors.New("unknown target")
+
+	// ErrUnknownEnvironment is returned when an environment is not in the config.
+	ErrUnknownEnvironment = errors.New("unknown environment")
+)
+
+// Config is the goschema project configuration, read from a goschema.yaml file. Relative paths in the file are
+// resolved against the directory of the file.
+type Config struct {
+	// Types are the rules that override the Go type of the columns they match. The first matching rule is used.
+	Types []TypeRule `yaml:"types"`
+
+	// Naming controls the names of the generated Go identifiers.
+	Naming Naming `yaml:"naming"`
+
+	// Tags configures the struct tags of the generated fields.
+	Tags Tags `yaml:"tags"`
+
+	// LegacySignatures generates the database methods without a context.Context, as they were before the context
+	// was added, so that callers can move to the new signatures gradually.
+	LegacySignatures bool `yaml:"legacy_signatures"`
+
+	// SoftDelete is the name of the nullable DATETIME or TIMESTAMP column that marks the rows of a table as soft
+	// deleted. It defaults to DefaultSoftDeleteColumn, and NoSoftDelete turns soft deletes off.
+	SoftDelete string `yaml:"soft_delete"`
+
+	// Version is the name of the integer column that versions the rows of a table for optimistic locking. Columns
+	// can also be marked with a `goschema:version` annotation.
+	Version string `yaml:"version"`
+
+	// Audit records the writes of the generated models in the audit_log table, which is created by the migration of
+	// `goschema create -audit`.
+	Audit bool `yaml:"audit"`
+
+	// Targets are the named sets of models to generate.
+	Targets map[string]*Target `yaml:"targets"`
+
+	// Environments are the named databases that migrations are applied to.
+	Environments map[string]*Environment `yaml:"environments"`
+
+	// DefaultEnvironment is the environment used by the migration commands when none is given.
+	DefaultEnvironment string `yaml:"default_environment"`
+}
+
+// Find returns the path of the config file in the given directory or the closest of its parents. ErrNotFound is
+// returned if there is no config file.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("error getting absolute path: %w", err)
+	}
+
+	for {
+		file := filepath.Join(dir, DefaultFile)
+		if _, err := os.Stat(file); err == nil {
+			return file, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("error checking for config file: %w", err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNotFound
+		}
+		dir = parent
+	}
+}
+
+// Load reads the config file at the given path.
+func Load(file string) (*Config, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("error opening config file: %w", err)
+	}
+	defer func(f *os.File) {
+		if err := f.Close(); err != nil {
+			slog.Warn("Error closing config file", slog.String(logging.KeyError, err.Error()))
+		}
+	}(f)
+
+	cfg, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, fmt.Errorf("error getting absolute path: %w", err)
+	}
+	cfg.resolvePaths(filepath.Dir(abs))
+
+	return cfg, nil
+}
+
+// Parse reads a config from the given reader. Unknown fields are rejected.
+func Parse(r io.Reader) (*Config, error) {
+	cfg := new(Config)
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) validate() error {
+	for i, rule := range c.Types {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("%w: types[%d]: %w", ErrInvalidConfig, i, err)
+		}
+	}
+
+	if err := c.Naming.validate(); err != nil {
+		return fmt.Errorf("%w: naming: %w", ErrInvalidConfig, err)
+	}
+
+	if err := c.Tags.validate(); err != nil {
+		return fmt.Errorf("%w: tags: %w", ErrInvalidConfig, err)
+	}
+
+	for name, t := range c.Targets {
+		if t == nil {
+			t = new(Target)
+			c.Targets[name] = t
+		}
+		t.Name = name
+		if err := t.validate(); err != nil {
+			return fmt.Errorf("%w: targets.%s: %w", ErrInvalidConfig, name, err)
+		}
+		if err := c.ForTarget(t).validateAudit(); err != nil {
+			return fmt.Errorf("%w: targets.%s: %w", ErrInvalidConfig, name, err)
+		}
+	}
+
+	if len(c.Targets) == 0 {
+		if err := c.validateAudit(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	for name, e := range c.Environments {
+		if e == nil {
+			e = new(Environment)
+			c.Environments[name] = e
+		}
+		e.Name = name
+		if err := e.validate(); err != nil {
+			return fmt.Errorf("%w: environments.%s: %w", ErrInvalidConfig, name, err)
+		}
+	}
+
+	if c.DefaultEnvironment != "" {
+		if _, ok := c.Environments[c.DefaultEnvironment]; !ok {
+			return fmt.Errorf("%w: default_environment: %w %q", ErrInvalidConfig, ErrUnknownEnvironment, c.DefaultEnvironment)
+		}
+	}
+
+	return nil
+}
+
+// validateAudit checks that the audited writes can be generated. The actor of the audit log is read from the context
+// of the writes, which the legacy signatures do not have.
+func (c *Config) validateAudit() error {
+	if c.Audit && c.LegacySignatures {
+		return errors.New("audit cannot be used with legacy_signatures")
+	}
+
+	return nil
+}
+
+// resolvePaths makes the relative paths of the config relative to the given directory
+func (c *Config) resolvePaths(dir string) {
+	for _, t := range c.Targets {
+		t.SQL = resolvePath(dir, t.SQL)
+		t.Migrations = resolvePath(dir, t.Migrations)
+		t.Out = resolvePath(dir, t.Out)
+		t.Templates = resolvePath(dir, t.Templates)
+	}
+
+	for _, e := range c.Environments {
+		e.Migrations = resolvePath(dir, e.Migrations)
+	}
+}
+
+func resolvePath(dir, p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+
+	return filepath.Join(dir, p)
+}
+
+// Target returns the target with the given name.
+func (c *Config) Target(name string) (*Target, error) {
+	t, ok := c.Targets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTarget, name)
+	}
+
+	return t, nil
+}
+
+// AllTargets returns the targets, sorted by name.
+func (c *Config) AllTargets() []*Target {
+	ret := make([]*Target, 0, len(c.Targets))
+	for _, t := range c.Targets {
+		ret = append(ret, t)
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Environment returns the environment with the given name, or the default environment if no name is given. A nil
+// environment is returned if no name is given and there is no default environment.
+func (c *Config) Environment(name string) (*Environment, error) {
+	if name == "" {
+		name = c.DefaultEnvironment
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	e, ok := c.Environments[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEnvironment, name)
+	}
+
+	return e, nil
+}
+
+// ForTarget returns the config to generate the given target with. The type rules of the target are checked before
+// the global type rules, and the other settings of the target replace the global settings.
+func (c *Config) ForTarget(t *Target) *Config {
+	cfg := *c
+	cfg.Types = append(append(make([]TypeRule, 0, len(t.Types)+len(c.Types)), t.Types...), c.Types...)
+	if t.Tags != nil {
+		cfg.Tags = *t.Tags
+	}
+	if t.LegacySignatures != nil {
+		cfg.LegacySignatures = *t.LegacySignatures
+	}
+	if t.SoftDelete != nil {
+		cfg.SoftDelete = *t.SoftDelete
+	}
+	if t.Version != nil {
+		cfg.Version = *t.Version
+	}
+	if t.Audit != nil {
+		cfg.Audit = *t.Audit
+	}
+	return &cfg
+}
+
+// SoftDeleteColumn returns the name of the column that marks soft deleted rows, which is empty if soft deletes are
+// turned off.
+func (c *Config) SoftDeleteColumn() string {
+	switch {
+	case c == nil || c.SoftDelete == "":
+		return DefaultSoftDeleteColumn
+	case c.SoftDelete == NoSoftDelete:
+		return ""
+	default:
+		return c.SoftDelete
+	}
+}
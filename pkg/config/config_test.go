@@ -0,0 +1,405 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr error
+	}{
+		{
+			name: "empty",
+			in:   "",
+		},
+		{
+			name: "type_rule",
+			in: `
+types:
+  - table: orders
+    column: "*_price"
+    go_type: money.Money
+    import: github.com/acme/money
+`,
+		},
+		{
+			name: "unknown_field",
+			in: `
+types:
+  - db_type: tinyint(1)
+    gotype: bool
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "missing_go_type",
+			in: `
+types:
+  - db_type: tinyint(1)
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "missing_matcher",
+			in: `
+types:
+  - go_type: bool
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "targets_and_environments",
+			in: `
+targets:
+  api:
+    sql: ./schemas/*.sql
+    out: ./internal/models
+    package: models
+environments:
+  local:
+    dsn: root:${DB_PASSWORD}@tcp(localhost:3306)/app
+  prod:
+    dsn_env: PROD_DATABASE_URL
+default_environment: local
+`,
+		},
+		{
+			name: "target_missing_out",
+			in: `
+targets:
+  api:
+    sql: ./schemas/*.sql
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "environment_with_both_dsns",
+			in: `
+environments:
+  local:
+    dsn: root@tcp(localhost:3306)/app
+    dsn_env: DATABASE_URL
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "unknown_default_environment",
+			in: `
+environments:
+  local:
+    dsn_env: DATABASE_URL
+default_environment: prod
+`,
+			wantErr: ErrUnknownEnvironment,
+		},
+		{
+			name: "naming",
+			in: `
+naming:
+  initialisms: [SKU]
+  tables:
+    people:
+      name: Person
+      plural: People
+      columns:
+        uid: UserID
+`,
+		},
+		{
+			name: "unexported_name",
+			in: `
+naming:
+  tables:
+    people:
+      name: person
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "empty_table_naming",
+			in: `
+naming:
+  tables:
+    people: {}
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "tags",
+			in: `
+tags:
+  json:
+    case: camel
+    omitempty: true
+  yaml: {}
+  validate: true
+`,
+		},
+		{
+			name: "reserved_tag",
+			in: `
+tags:
+  db: {}
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "invalid_tag_case",
+			in: `
+tags:
+  json:
+    case: kebab
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "audit_legacy_signatures",
+			in: `
+audit: true
+legacy_signatures: true
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "target_audit_legacy_signatures",
+			in: `
+audit: true
+targets:
+  api:
+    sql: ./schemas/*.sql
+    out: ./internal/models
+    legacy_signatures: true
+`,
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name: "target_audit_context_signatures",
+			in: `
+audit: true
+legacy_signatures: true
+targets:
+  api:
+    sql: ./schemas/*.sql
+    out: ./internal/models
+    legacy_signatures: false
+`,
+		},
+		{
+			name: "invalid_db_type",
+			in: `
+types:
+  - db_type: tinyint(
+    go_type: bool
+`,
+			wantErr: ErrInvalidConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.in))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypeRuleMatches(t *testing.T) {
+	notNull := false
+
+	tests := []struct {
+		name  string
+		rule  TypeRule
+		table string
+		col   *entities.Column
+		want  bool
+	}{
+		{
+			name:  "db_type_with_size",
+			rule:  TypeRule{DBType: "tinyint(1)"},
+			table: "users",
+			col:   &entities.Column{Name: "active", Type: "tinyint", TypeSize: 1, Nullable: true},
+			want:  true,
+		},
+		{
+			name:  "db_type_size_mismatch",
+			rule:  TypeRule{DBType: "tinyint(1)"},
+			table: "users",
+			col:   &entities.Column{Name: "age", Type: "tinyint", TypeSize: 4},
+			want:  false,
+		},
+		{
+			name:  "decimal_precision_and_scale",
+			rule:  TypeRule{DBType: "DECIMAL(10,2)"},
+			table: "orders",
+			col:   &entities.Column{Name: "total", Type: "decimal", TypeSize: 10, TypePrecision: 2},
+			want:  true,
+		},
+		{
+			name:  "datetime_fractional_seconds",
+			rule:  TypeRule{DBType: "datetime(6)"},
+			table: "orders",
+			col:   &entities.Column{Name: "created_at", Type: "datetime", TypeSize: 26, TypePrecision: 6},
+			want:  true,
+		},
+		{
+			name:  "globs",
+			rule:  TypeRule{Table: "order*", Column: "*_price"},
+			table: "order_items",
+			col:   &entities.Column{Name: "unit_price", Type: "decimal"},
+			want:  true,
+		},
+		{
+			name:  "nullable_mismatch",
+			rule:  TypeRule{Column: "*_price", Nullable: &notNull},
+			table: "order_items",
+			col:   &entities.Column{Name: "unit_price", Type: "decimal", Nullable: true},
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches(tt.table, tt.col); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "internal", "models")
+	if err := os.MkdirAll(nested, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Find(nested); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Find() error = %v, want %v", err, ErrNotFound)
+	}
+
+	want := filepath.Join(root, DefaultFile)
+	if err := os.WriteFile(want, []byte("targets:\n  api:\n    sql: ./schemas/*.sql\n    out: ./internal/models\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Find(nested)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+
+	cfg, err := Load(got)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if out := cfg.Targets["api"].Out; out != nested {
+		t.Errorf("Load() target out = %v, want %v", out, nested)
+	}
+}
+
+func TestForTarget(t *testing.T) {
+	cfg := &Config{Types: []TypeRule{{DBType: "tinyint(1)", GoType: "bool"}}}
+	target := &Target{Types: []TypeRule{{DBType: "tinyint(1)", GoType: "int8"}}}
+
+	col := &entities.Column{Name: "active", Type: "tinyint", TypeSize: 1}
+	rule, ok := cfg.ForTarget(target).TypeRule("users", col)
+	if !ok || rule.GoType != "int8" {
+		t.Errorf("ForTarget().TypeRule() = %v, %v, want the target rule", rule.GoType, ok)
+	}
+
+	if len(cfg.Types) != 1 {
+		t.Errorf("ForTarget() changed the global type rules")
+	}
+
+	legacy := true
+	if !cfg.ForTarget(&Target{LegacySignatures: &legacy}).LegacySignatures {
+		t.Errorf("ForTarget() LegacySignatures = false, want the target setting")
+	}
+
+	softDelete := "removed_at"
+	if got := cfg.ForTarget(&Target{SoftDelete: &softDelete}).SoftDeleteColumn(); got != softDelete {
+		t.Errorf("ForTarget() SoftDeleteColumn() = %q, want the target setting", got)
+	}
+
+	version := "lock_version"
+	if got := cfg.ForTarget(&Target{Version: &version}).Version; got != version {
+		t.Errorf("ForTarget() Version = %q, want the target setting", got)
+	}
+
+	audit := true
+	if !cfg.ForTarget(&Target{Audit: &audit}).Audit {
+		t.Errorf("ForTarget() Audit = false, want the target setting")
+	}
+}
+
+func TestSoftDeleteColumn(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{name: "nil", want: DefaultSoftDeleteColumn},
+		{name: "default", cfg: new(Config), want: DefaultSoftDeleteColumn},
+		{name: "configured", cfg: &Config{SoftDelete: "archived_at"}, want: "archived_at"},
+		{name: "off", cfg: &Config{SoftDelete: NoSoftDelete}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.SoftDeleteColumn(); got != tt.want {
+				t.Errorf("SoftDeleteColumn() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentDataSourceName(t *testing.T) {
+	t.Setenv("GOSCHEMA_TEST_PASSWORD", "secret")
+	t.Setenv("GOSCHEMA_TEST_DSN", "root@tcp(db:3306)/app")
+
+	tests := []struct {
+		name    string
+		env     Environment
+		want    string
+		wantErr error
+	}{
+		{
+			name: "dsn",
+			env:  Environment{DSN: "root:${GOSCHEMA_TEST_PASSWORD}@tcp(localhost:3306)/app"},
+			want: "root:secret@tcp(localhost:3306)/app",
+		},
+		{
+			name: "dsn_env",
+			env:  Environment{DSNEnv: "GOSCHEMA_TEST_DSN"},
+			want: "root@tcp(db:3306)/app",
+		},
+		{
+			name:    "dsn_env_not_set",
+			env:     Environment{DSNEnv: "GOSCHEMA_TEST_UNSET"},
+			wantErr: ErrNoDSN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.env.DataSourceName()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DataSourceName() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DataSourceName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoDSN is returned when the DSN of an environment is empty.
+var ErrNoDSN = errors.New("no database DSN")
+
+// Environment is a named database that migrations are applied to.
+type Environment struct {
+	// Name is the name of the environment, taken from its key in the config.
+	Name string `yaml:"-"`
+
+	// DSN is the data source name of the database. Environment variables in the form ${VAR} are expanded, so
+	// credentials do not need to be kept in the config.
+	DSN string `yaml:"dsn"`
+
+	// DSNEnv is the name of an environment variable holding the data source name.
+	DSNEnv string `yaml:"dsn_env"`
+
+	// Migrations is the directory of the migrations.
+	Migrations string `yaml:"migrations"`
+}
+
+func (e *Environment) validate() error {
+	if e.DSN == "" && e.DSNEnv == "" {
+		return errors.New("one of dsn or dsn_env is required")
+	}
+	if e.DSN != "" && e.DSNEnv != "" {
+		return errors.New("only one of dsn or dsn_env can be given")
+	}
+
+	return nil
+}
+
+// DataSourceName returns the data source name of the environment's database.
+func (e *Environment) DataSourceName() (string, error) {
+	dsn := os.ExpandEnv(e.DSN)
+	if e.DSNEnv != "" {
+		dsn = os.Getenv(e.DSNEnv)
+	}
+
+	if dsn == "" {
+		if e.DSNEnv != "" {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrNoDSN, e.DSNEnv)
+		}
+		return "", ErrNoDSN
+	}
+
+	return dsn, nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// errEmptyNaming is returned when a table of the naming config has no overrides
+var errEmptyNaming = errors.New("one of name, plural or columns is required")
+
+// Naming controls the names of the generated Go identifiers.
+type Naming struct {
+	// Initialisms are kept in upper case in Go names, in addition to the standard Go initialisms such as ID and
+	// URL, e.g. "SKU".
+	Initialisms []string `yaml:"initialisms"`
+
+	// Tables overrides the names generated for a table and its columns, keyed by table name.
+	Tables map[string]*TableNaming `yaml:"tables"`
+}
+
+// TableNaming overrides the names generated for a table and its columns.
+type TableNaming struct {
+	// Name is the name of the table's struct, e.g. "Person" for the table "people".
+	Name string `yaml:"name"`
+
+	// Plural is the plural of the struct name, used by functions such as GetAll<Plural>, e.g. "People".
+	Plural string `yaml:"plural"`
+
+	// Columns are the names of the struct fields, keyed by column name, e.g. "uid: UserID".
+	Columns map[string]string `yaml:"columns"`
+}
+
+func (n *Naming) validate() error {
+	for i, initialism := range n.Initialisms {
+		if initialism == "" || strings.IndexFunc(initialism, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) >= 0 {
+			return fmt.Errorf("initialisms[%d]: %q must only contain letters and digits", i, initialism)
+		}
+	}
+
+	for table, t := range n.Tables {
+		if t == nil || (t.Name == "" && t.Plural == "" && len(t.Columns) == 0) {
+			return fmt.Errorf("tables.%s: %w", table, errEmptyNaming)
+		}
+		if err := validateName(t.Name); err != nil {
+			return fmt.Errorf("tables.%s.name: %w", table, err)
+		}
+		if err := validateName(t.Plural); err != nil {
+			return fmt.Errorf("tables.%s.plural: %w", table, err)
+		}
+		for column, name := range t.Columns {
+			if err := validateName(name); err != nil {
+				return fmt.Errorf("tables.%s.columns.%s: %w", table, column, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateName checks that the name, if given, can be used as an exported Go identifier
+func validateName(name string) error {
+	if name == "" {
+		return nil
+	}
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return fmt.Errorf("%q is not an exported Go identifier", name)
+	}
+
+	return nil
+}
+
+// TableNaming returns the naming overrides of the given table.
+func (c *Config) TableNaming(table string) (*TableNaming, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	for name, t := range c.Naming.Tables {
+		if t != nil && strings.EqualFold(name, table) {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// ColumnName returns the overridden name of the given column of a table.
+func (t *TableNaming) ColumnName(column string) (string, bool) {
+	for name, override := range t.Columns {
+		if strings.EqualFold(name, column) {
+			return override, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+const (
+	// CaseSnake names a tag after the column in snake case, e.g. "user_id". It is the default case.
+	CaseSnake = "snake"
+
+	// CaseCamel names a tag after the column in camel case, e.g. "userId".
+	CaseCamel = "camel"
+)
+
+// tagKeyRegex matches a valid struct tag key
+var tagKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedTags are the struct tags that goschema relies on, so they can not be configured
+var reservedTags = map[string]struct{}{
+	"db":      {},
+	"patcher": {},
+}
+
+// Tags configures the struct tags of the generated fields. The db tag is always generated.
+type Tags struct {
+	// Names are the tags holding the name of the column, keyed by tag, e.g. json, yaml or mapstructure.
+	Names map[string]*NameTag `yaml:",inline"`
+
+	// Validate adds a validate tag with the rules that follow from the column, for use with
+	// github.com/go-playground/validator.
+	Validate bool `yaml:"validate"`
+}
+
+// NameTag is a struct tag holding the name of the column, such as `json:"user_id,omitempty"`.
+type NameTag struct {
+	// Case is the case of the name, either "snake" or "camel". It defaults to "snake".
+	Case string `yaml:"case"`
+
+	// OmitEmpty adds the omitempty option for nullable columns.
+	OmitEmpty bool `yaml:"omitempty"`
+}
+
+func (t *Tags) validate() error {
+	for key, tag := range t.Names {
+		if !IsValidTagKey(key) {
+			return fmt.Errorf("%q is not a valid struct tag key", key)
+		}
+		if IsReservedTag(key) {
+			return fmt.Errorf("%q is generated by goschema and can not be configured", key)
+		}
+		if tag == nil {
+			tag = new(NameTag)
+			t.Names[key] = tag
+		}
+		switch tag.Case {
+		case "", CaseSnake, CaseCamel:
+		default:
+			return fmt.Errorf("%s.case: %q must be %q or %q", key, tag.Case, CaseSnake, CaseCamel)
+		}
+	}
+
+	return nil
+}
+
+// NameKeys returns the keys of the name tags, sorted.
+func (t *Tags) NameKeys() []string {
+	keys := make([]string, 0, len(t.Names))
+	for key := range t.Names {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// IsReservedTag returns true if the struct tag is generated by goschema and can not be set by the config or an
+// annotation.
+func IsReservedTag(key string) bool {
+	_, ok := reservedTags[key]
+	return ok
+}
+
+// IsValidTagKey returns true if the key can be used as a struct tag key.
+func IsValidTagKey(key string) bool {
+	return tagKeyRegex.MatchString(key)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Target is a named set of models to generate.
+type Target struct {
+	// Name is the name of the target, taken from its key in the config.
+	Name string `yaml:"-"`
+
+	// SQL is a glob of the SQL files that hold the schema, e.g. "./schemas/*.sql".
+	SQL string `yaml:"sql"`
+
+	// Migrations is the directory of migrations to build the schema from. It is used instead of SQL when set.
+	Migrations string `yaml:"migrations"`
+
+	// Out is the directory to write the generated files to.
+	Out string `yaml:"out"`
+
+	// Package is the name of the generated package. It defaults to the name of the output directory.
+	Package string `yaml:"package"`
+
+	// Templates is a glob of the templates to use. The built-in templates are used when it is empty.
+	Templates string `yaml:"templates"`
+
+	// Extension is the prefix to add to the generated file extension, e.g. "xo" for "users.xo.go". No prefix
+	// is added when it is empty.
+	Extension string `yaml:"extension"`
+
+	// Types are the type rules of the target. They are checked before the global type rules.
+	Types []TypeRule `yaml:"types"`
+
+	// Tags replaces the global struct tags for the target when set.
+	Tags *Tags `yaml:"tags"`
+
+	// LegacySignatures replaces the global legacy_signatures setting for the target when set.
+	LegacySignatures *bool `yaml:"legacy_signatures"`
+
+	// SoftDelete replaces the global soft_delete setting for the target when set.
+	SoftDelete *string `yaml:"soft_delete"`
+
+	// Version replaces the global version setting for the target when set.
+	Version *string `yaml:"version"`
+
+	// Audit replaces the global audit setting for the target when set.
+	Audit *bool `yaml:"audit"`
+}
+
+func (t *Target) validate() error {
+	if t.SQL == "" && t.Migrations == "" {
+		return errors.New("one of sql or migrations is required")
+	}
+	if t.Out == "" {
+		return errors.New("out is required")
+	}
+
+	for i, rule := range t.Types {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("types[%d]: %w", i, err)
+		}
+	}
+
+	if t.Tags != nil {
+		if err := t.Tags.validate(); err != nil {
+			return fmt.Errorf("tags: %w", err)
+		}
+	}
+
+	return nil
+}
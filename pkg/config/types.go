@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// dbTypeRegex matches a MySQL type with optional arguments, e.g. "tinyint(1)" or "decimal(10,2)"
+var dbTypeRegex = regexp.MustCompile(`^([a-z]+)(?:\((\d+)(?:,(\d+))?\))?$`)
+
+// TypeRule overrides the Go type of the columns it matches. Empty fields match any column.
+type TypeRule struct {
+	// Table is a glob matched against the table name, e.g. "users" or "audit_*".
+	Table string `yaml:"table"`
+
+	// Column is a glob matched against the column name, e.g. "*_price".
+	Column string `yaml:"column"`
+
+	// DBType is the MySQL type of the column, e.g. "tinyint(1)", "decimal(10,2)" or "datetime(6)". When the size
+	// is left out, columns of any size match.
+	DBType string `yaml:"db_type"`
+
+	// Nullable restricts the rule to nullable or not null columns when set.
+	Nullable *bool `yaml:"nullable"`
+
+	// GoType is the Go type of the matched columns, e.g. "bool" or "money.Money".
+	GoType string `yaml:"go_type"`
+
+	// Import is the import path of the package GoType is declared in, e.g. "github.com/acme/money".
+	Import string `yaml:"import"`
+}
+
+// TypeRule returns the first type rule that matches the column of the given table.
+func (c *Config) TypeRule(table string, col *entities.Column) (TypeRule, bool) {
+	if c == nil {
+		return TypeRule{}, false
+	}
+
+	for _, rule := range c.Types {
+		if rule.Matches(table, col) {
+			return rule, true
+		}
+	}
+
+	return TypeRule{}, false
+}
+
+func (r TypeRule) validate() error {
+	if r.GoType == "" {
+		return errors.New("go_type is required")
+	}
+	if r.Table == "" && r.Column == "" && r.DBType == "" && r.Nullable == nil {
+		return errors.New("at least one of table, column, db_type or nullable is required")
+	}
+	for _, glob := range []string{r.Table, r.Column} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+	if r.DBType != "" && !dbTypeRegex.MatchString(strings.ToLower(r.DBType)) {
+		return fmt.Errorf("invalid db_type %q", r.DBType)
+	}
+
+	return nil
+}
+
+// Matches returns true if the rule applies to the column of the given table.
+func (r TypeRule) Matches(table string, col *entities.Column) bool {
+	if r.Nullable != nil && *r.Nullable != col.Nullable {
+		return false
+	}
+	if !matchGlob(r.Table, table) || !matchGlob(r.Column, col.Name) {
+		return false
+	}
+
+	return r.DBType == "" || matchDBType(r.DBType, col)
+}
+
+// matchGlob returns true if the name matches the glob. An empty glob matches any name.
+func matchGlob(glob, name string) bool {
+	if glob == "" {
+		return true
+	}
+
+	// The glob has been validated, so the error can be ignored.
+	ok, _ := path.Match(strings.ToLower(glob), strings.ToLower(name))
+	return ok
+}
+
+// matchDBType returns true if the column has the given MySQL type. The arguments of temporal types are the
+// fractional seconds precision, which is held in the column's TypePrecision.
+func matchDBType(dbType string, col *entities.Column) bool {
+	m := dbTypeRegex.FindStringSubmatch(strings.ToLower(dbType))
+	if m == nil || m[1] != strings.ToLower(col.Type) {
+		return false
+	}
+
+	size, scale := col.TypeSize, col.TypePrecision
+	switch m[1] {
+	case "datetime", "timestamp", "time":
+		size = col.TypePrecision
+	}
+
+	if m[2] != "" && !matchArg(m[2], size) {
+		return false
+	}
+
+	return m[3] == "" || matchArg(m[3], scale)
+}
+
+func matchArg(arg string, v int) bool {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		return false
+	}
+
+	return n == max(v, 0)
+}
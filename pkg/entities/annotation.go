@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// AnnotationType sets the Go type of a column, e.g. `goschema:type=github.com/acme/pkg.Settings`.
+	AnnotationType = "type"
+
+	// AnnotationName sets the Go name of a table's struct or a column's field, e.g. `goschema:name=Person`.
+	AnnotationName = "name"
+
+	// AnnotationPlural sets the plural of a table's struct name, e.g. `goschema:plural=People`.
+	AnnotationPlural = "plural"
+
+	// AnnotationTagPrefix prefixes the annotations that add a struct tag to a column's field, e.g.
+	// `goschema:tag_json=-` adds `json:"-"`.
+	AnnotationTagPrefix = "tag_"
+
+	// AnnotationVersion marks the integer column that versions the rows of a table for optimistic locking, e.g.
+	// `goschema:version`.
+	AnnotationVersion = "version"
+
+	// AnnotationCreatedAt marks the column that is set to the time a row is inserted, e.g. `goschema:created_at`.
+	AnnotationCreatedAt = "created_at"
+
+	// AnnotationUpdatedAt marks the column that is set to the time a row is written, e.g. `goschema:updated_at`.
+	AnnotationUpdatedAt = "updated_at"
+)
+
+var (
+	// ErrInvalidAnnotation is returned when a table or column comment contains an annotation that can not be parsed.
+	ErrInvalidAnnotation = errors.New("invalid annotation")
+
+	// annotationRegex matches `goschema:key=value` and `goschema:key` annotations in a comment.
+	annotationRegex = regexp.MustCompile(`(^|\s)goschema:([a-z_]+)(=(\S*))?`)
+)
+
+// parseAnnotations returns the comment with any goschema annotations removed, along with the annotations.
+func parseAnnotations(comment string) (string, map[string]string, error) {
+	matches := annotationRegex.FindAllStringSubmatch(comment, -1)
+	if len(matches) == 0 {
+		return comment, nil, nil
+	}
+
+	annotations := make(map[string]string, len(matches))
+	for _, m := range matches {
+		key, hasValue, value := m[2], m[3] != "", m[4]
+		if hasValue && value == "" {
+			return "", nil, fmt.Errorf("%w: %q has no value", ErrInvalidAnnotation, key)
+		}
+		if _, ok := annotations[key]; ok {
+			return "", nil, fmt.Errorf("%w: %q is given more than once", ErrInvalidAnnotation, key)
+		}
+		annotations[key] = value
+	}
+
+	return strings.TrimSpace(annotationRegex.ReplaceAllString(comment, "")), annotations, nil
+}
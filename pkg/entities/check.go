@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
+)
+
+// Check represents a MySQL CHECK constraint
+type Check struct {
+	Name       string
+	Expression string
+	Enforced   bool
+
+	// Column is set when the check was declared as part of a column definition.
+	Column string
+}
+
+// newCheck returns a Check representing the given CHECK constraint
+func newCheck(name string, expr ast.ExprNode, enforced bool) (Check, error) {
+	e, err := restoreExpr(expr)
+	if err != nil {
+		return Check{}, err
+	}
+
+	return Check{
+		Name:       name,
+		Expression: e,
+		Enforced:   enforced,
+	}, nil
+}
+
+// restoreExpr returns the SQL text of the given expression
+func restoreExpr(expr ast.ExprNode) (string, error) {
+	sb := new(strings.Builder)
+	if err := expr.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, sb)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -1,12 +1,14 @@
 package entities
 
 import (
+	"fmt"
 	"log/slog"
 	"math/big"
 	"time"
 
 	"github.com/jacobbrewer1/goschema/pkg/logging"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/charset"
 	"github.com/pingcap/tidb/pkg/parser/mysql"
 	"github.com/pingcap/tidb/pkg/parser/types"
 )
@@ -14,6 +16,9 @@ import (
 const (
 	// TypeEnum represents a MySQL enum type. This is because ENUMS are not a real type in MySQL, they are just a list of strings.
 	TypeEnum = "enum"
+
+	// TypeSet represents a MySQL set type, which holds any number of the elements of the column.
+	TypeSet = "set"
 )
 
 // FunctionCall represents a MySQL function call
@@ -36,26 +41,70 @@ type Column struct {
 	InUniqueKey      bool
 	Comment          string
 	Elements         []string
+	Charset          string
+	Collation        string
+
+	// Generated is true for generated columns, which are computed from GeneratedExpr and cannot be written to.
+	Generated       bool
+	GeneratedExpr   string
+	GeneratedStored bool
+
+	// Invisible is true for columns that are hidden from `SELECT *` queries.
+	Invisible bool
+
+	// Source is the table column that a view column is selected from, and nil for table columns and for view columns
+	// that are computed.
+	Source *Column
+
+	// Annotations holds the `goschema:key=value` annotations read from the column comment. They are removed from
+	// Comment.
+	Annotations map[string]string
+
+	// fieldType is the parsed type of the column, kept for applying later alterations.
+	fieldType *types.FieldType
+}
+
+// newColumn returns a Column representing the given MySQL column definition
+func newColumn(def *ast.ColumnDef) (*Column, error) {
+	c := &Column{
+		Name:      def.Name.String(),
+		fieldType: def.Tp,
+	}
+	c.setTypeInfo(def.Tp)
+	c.setFlags(def.Tp.GetFlag())
+	if err := c.setOptions(def); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Annotation returns the value of the given annotation, and whether the column has it.
+func (c *Column) Annotation(key string) (string, bool) {
+	v, ok := c.Annotations[key]
+	return v, ok
 }
 
 func (c *Column) setTypeInfo(tp *types.FieldType) {
-	switch {
-	case tp.GetType() == mysql.TypeTiny:
-		c.Type = "tinyint"
-	case tp.GetType() == mysql.TypeShort:
-		c.Type = "smallint"
-	case tp.GetType() == mysql.TypeInt24:
-		c.Type = "mediumint"
-	case tp.GetType() == mysql.TypeLonglong:
-		c.Type = "bigint"
-	default:
-		c.Type = tp.EvalType().String()
+	// Use the MySQL type name, e.g. "varbinary" rather than "varchar" with a binary charset.
+	c.Type = types.TypeToStr(tp.GetType(), tp.GetCharset())
+	if tp.GetType() == mysql.TypeVarString {
+		c.Type = "varchar"
+		if tp.GetCharset() == charset.CharsetBin {
+			c.Type = "varbinary"
+		}
 	}
 	c.TypeSize = tp.GetFlen()
 	c.TypePrecision = tp.GetDecimal()
-	if tp.GetType() == mysql.TypeEnum { // nolint:revive // Allow for extendability on the method signature
+	c.Charset = tp.GetCharset()
+	c.Collation = tp.GetCollate()
+	switch tp.GetType() {
+	case mysql.TypeEnum:
 		c.Type = TypeEnum
 		c.Elements = tp.GetElems()
+	case mysql.TypeSet:
+		c.Type = TypeSet
+		c.Elements = tp.GetElems()
 	}
 }
 
@@ -77,7 +126,7 @@ func (c *Column) setOptions(col *ast.ColumnDef) error {
 				if v == nil || v.GetValue() == nil {
 					return nil
 				}
-				if err := c.setDefaultValue(col, v); err != nil {
+				if err := c.setDefaultValue(col.Tp, v); err != nil {
 					return err
 				}
 			default:
@@ -89,13 +138,30 @@ func (c *Column) setOptions(col *ast.ColumnDef) error {
 		case ast.ColumnOptionAutoIncrement:
 			c.AutoIncrementing = true
 		case ast.ColumnOptionComment:
-			c.Comment = opt.Expr.Text()
+			comment, annotations, err := parseAnnotations(opt.Expr.Text())
+			if err != nil {
+				return fmt.Errorf("column %s: %w", c.Name, err)
+			}
+			c.Comment = comment
+			c.Annotations = annotations
 		case ast.ColumnOptionPrimaryKey:
 			c.InPrimaryKey = true
 			c.InUniqueKey = true
 			c.Nullable = false // Primary keys are not nullable
 		case ast.ColumnOptionUniqKey:
 			c.InUniqueKey = true
+		case ast.ColumnOptionGenerated:
+			expr, err := restoreExpr(opt.Expr)
+			if err != nil {
+				return fmt.Errorf("error reading generated column expression: %w", err)
+			}
+			c.Generated = true
+			c.GeneratedExpr = expr
+			c.GeneratedStored = opt.Stored
+		case ast.ColumnOptionCollate:
+			c.Collation = opt.StrValue
+		case ast.ColumnOptionCheck:
+			// Checks are held by the table.
 		default:
 			// Ignore other options
 			slog.Warn("Unhandled column option", slog.Int(logging.KeyType, int(opt.Tp)))
@@ -104,16 +170,16 @@ func (c *Column) setOptions(col *ast.ColumnDef) error {
 	return nil
 }
 
-func (c *Column) setDefaultValue(col *ast.ColumnDef, v ast.ValueExpr) (err error) {
+func (c *Column) setDefaultValue(tp *types.FieldType, v ast.ValueExpr) (err error) {
 	if v.GetValue() == nil {
 		return nil
 	}
 
 	// We mostly ignore errors because we are only parsing - not validating. If you write
 	// invalid schemas then schema2go should try to do the right thing, or at least the least-wrong thing.
-	switch col.Tp.EvalType() {
+	switch tp.EvalType() {
 	case types.ETDatetime:
-		if col.Tp.GetType() == mysql.TypeDate {
+		if tp.GetType() == mysql.TypeDate {
 			c.Default, err = time.Parse(time.DateOnly, v.GetString())
 			if err != nil {
 				c.Default = time.Time{}
@@ -157,7 +223,7 @@ func (c *Column) setDefaultValue(col *ast.ColumnDef, v ast.ValueExpr) (err error
 			return nil
 		case mysql.TypeNewDecimal:
 			d := v.GetString()
-			precision := col.Tp.GetFlen()
+			precision := tp.GetFlen()
 
 			var prec uint
 			if precision >= 0 {
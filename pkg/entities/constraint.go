@@ -10,11 +10,22 @@ type Constraint struct {
 	ReferenceTable string
 	References     map[string]string
 	Comment        string
+
+	// Columns are the local columns of the foreign key, in the order of the constraint.
+	Columns []string
+
+	// ReferenceColumns are the referenced columns, in the same order as Columns.
+	ReferenceColumns []string
 }
 
 func (c *Constraint) setReferences(con *ast.Constraint) {
 	c.References = make(map[string]string, len(con.Keys))
+	c.Columns = make([]string, 0, len(con.Keys))
+	c.ReferenceColumns = make([]string, 0, len(con.Keys))
 	for i, col := range con.Keys {
-		c.References[col.Column.String()] = con.Refer.IndexPartSpecifications[i].Column.String()
+		ref := con.Refer.IndexPartSpecifications[i].Column.String()
+		c.References[col.Column.String()] = ref
+		c.Columns = append(c.Columns, col.Column.String())
+		c.ReferenceColumns = append(c.ReferenceColumns, ref)
 	}
 }
@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+)
+
+// The statements are built by hand so that the schema is tested independently of the parser.
+
+func tableName(name string) *ast.TableName {
+	return &ast.TableName{Name: model.NewCIStr(name)}
+}
+
+func columnName(name string) *ast.ColumnName {
+	return &ast.ColumnName{Name: model.NewCIStr(name)}
+}
+
+func columnDef(name string, tp byte, opts ...ast.ColumnOptionType) *ast.ColumnDef {
+	def := &ast.ColumnDef{Name: columnName(name), Tp: types.NewFieldType(tp)}
+	for _, opt := range opts {
+		def.Options = append(def.Options, &ast.ColumnOption{Tp: opt})
+	}
+	return def
+}
+
+func intColumn(name string, opts ...ast.ColumnOptionType) *ast.ColumnDef {
+	return columnDef(name, mysql.TypeLong, opts...)
+}
+
+func varcharColumn(name string, opts ...ast.ColumnOptionType) *ast.ColumnDef {
+	return columnDef(name, mysql.TypeVarchar, opts...)
+}
+
+func keyParts(cols ...string) []*ast.IndexPartSpecification {
+	parts := make([]*ast.IndexPartSpecification, len(cols))
+	for i, col := range cols {
+		parts[i] = &ast.IndexPartSpecification{Column: columnName(col)}
+	}
+	return parts
+}
+
+func primaryKey(cols ...string) *ast.Constraint {
+	return &ast.Constraint{Tp: ast.ConstraintPrimaryKey, Keys: keyParts(cols...)}
+}
+
+func uniqueKey(name string, cols ...string) *ast.Constraint {
+	return &ast.Constraint{Tp: ast.ConstraintUniqKey, Name: name, Keys: keyParts(cols...)}
+}
+
+func foreignKey(name string, cols []string, table string, refs ...string) *ast.Constraint {
+	return &ast.Constraint{
+		Tp:    ast.ConstraintForeignKey,
+		Name:  name,
+		Keys:  keyParts(cols...),
+		Refer: &ast.ReferenceDef{Table: tableName(table), IndexPartSpecifications: keyParts(refs...)},
+	}
+}
+
+func createTable(name string, cols []*ast.ColumnDef, cons ...*ast.Constraint) *ast.CreateTableStmt {
+	return &ast.CreateTableStmt{Table: tableName(name), Cols: cols, Constraints: cons}
+}
+
+func alterTable(name string, specs ...*ast.AlterTableSpec) *ast.AlterTableStmt {
+	return &ast.AlterTableStmt{Table: tableName(name), Specs: specs}
+}
+
+// describe returns a compact description of the tables in the schema, e.g.
+//
+//	users(id int pk, email varchar null uq) primary(id) uniq_email:unique_key(email)
+//
+// Foreign keys are described as `fk_name(col)->table(ref)`.
+func describe(s *Schema) []string {
+	ret := make([]string, 0, len(s.Tables()))
+	for _, t := range s.Tables() {
+		cols := make([]string, len(t.Columns))
+		for i, col := range t.Columns {
+			desc := col.Name + " " + col.Type
+			if col.Nullable {
+				desc += " null"
+			}
+			switch {
+			case col.InPrimaryKey:
+				desc += " pk"
+			case col.InUniqueKey:
+				desc += " uq"
+			}
+			cols[i] = desc
+		}
+
+		parts := []string{fmt.Sprintf("%s(%s)", t.Name, strings.Join(cols, ", "))}
+		if t.PrimaryKey != nil {
+			parts = append(parts, fmt.Sprintf("%s(%s)", t.PrimaryKey.Name, keyColumns(t.PrimaryKey.Columns)))
+		}
+		for _, k := range t.Keys {
+			parts = append(parts, fmt.Sprintf("%s:%s(%s)", k.Name, k.Type, keyColumns(k.Columns)))
+		}
+		for _, c := range t.Constraints {
+			refs := make([]string, len(c.Columns))
+			for i, col := range c.Columns {
+				refs[i] = c.References[col]
+			}
+			parts = append(parts, fmt.Sprintf("%s(%s)->%s(%s|%s)", c.Name, strings.Join(c.Columns, ", "),
+				c.ReferenceTable, strings.Join(c.ReferenceColumns, ", "), strings.Join(refs, ", ")))
+		}
+
+		ret = append(ret, strings.Join(parts, " "))
+	}
+
+	return ret
+}
+
+func keyColumns(cols []*Column) string {
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,177 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+// Partitioning represents the partitioning of a MySQL table
+type Partitioning struct {
+	// Type is the partitioning type, e.g. RANGE, LIST, HASH or KEY.
+	Type   string
+	Linear bool
+
+	// Expression is the partitioning expression of RANGE, LIST and HASH partitioning.
+	Expression string
+
+	// Columns are the partitioning columns of KEY, RANGE COLUMNS and LIST COLUMNS partitioning.
+	Columns []string
+
+	// Count is the number of partitions.
+	Count uint64
+
+	Definitions []PartitionDefinition
+
+	// SubPartitioning is the partitioning of each partition, if any.
+	SubPartitioning *Partitioning
+}
+
+// PartitionDefinition represents a single partition of a MySQL table
+type PartitionDefinition struct {
+	Name string
+
+	// Values is the value clause of the partition, e.g. `LESS THAN (1000)` or `IN (1, 2, 3)`.
+	Values  string
+	Comment string
+}
+
+// newPartitioning returns a Partitioning representing the given partition options
+func newPartitioning(opts *ast.PartitionOptions) (*Partitioning, error) {
+	p, err := newPartitionMethod(&opts.PartitionMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Sub != nil {
+		if p.SubPartitioning, err = newPartitionMethod(opts.Sub); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.addDefinitions(opts.Definitions); err != nil {
+		return nil, err
+	}
+
+	if p.Count == 0 {
+		p.Count = uint64(len(p.Definitions))
+	}
+
+	return p, nil
+}
+
+func newPartitionMethod(m *ast.PartitionMethod) (*Partitioning, error) {
+	p := &Partitioning{
+		Type:   m.Tp.String(),
+		Linear: m.Linear,
+		Count:  m.Num,
+	}
+
+	if m.Expr != nil {
+		expr, err := restoreExpr(m.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading partition expression: %w", err)
+		}
+		p.Expression = expr
+	}
+
+	for _, col := range m.ColumnNames {
+		p.Columns = append(p.Columns, col.Name.L)
+	}
+
+	return p, nil
+}
+
+// addDefinitions adds the given partitions
+func (p *Partitioning) addDefinitions(defs []*ast.PartitionDefinition) error {
+	for _, def := range defs {
+		values, err := partitionValues(def.Clause)
+		if err != nil {
+			return fmt.Errorf("error reading partition %q: %w", def.Name.O, err)
+		}
+
+		d := PartitionDefinition{
+			Name:   def.Name.O,
+			Values: values,
+		}
+		d.Comment, _ = def.Comment()
+
+		p.Definitions = append(p.Definitions, d)
+	}
+
+	return nil
+}
+
+// clone returns a deep copy of the partitioning
+func (p *Partitioning) clone() *Partitioning {
+	if p == nil {
+		return nil
+	}
+
+	cp := *p
+	cp.Columns = slices.Clone(p.Columns)
+	cp.Definitions = slices.Clone(p.Definitions)
+	cp.SubPartitioning = p.SubPartitioning.clone()
+	return &cp
+}
+
+// dropDefinitions removes the named partitions
+func (p *Partitioning) dropDefinitions(names []string) error {
+	for _, name := range names {
+		idx := slices.IndexFunc(p.Definitions, func(d PartitionDefinition) bool { return strings.EqualFold(d.Name, name) })
+		if idx == -1 {
+			return fmt.Errorf("partition %q does not exist", name)
+		}
+		p.Definitions = slices.Delete(p.Definitions, idx, idx+1)
+	}
+
+	p.Count = uint64(len(p.Definitions))
+	return nil
+}
+
+// partitionValues returns the SQL text of the value clause of a partition
+func partitionValues(clause ast.PartitionDefinitionClause) (string, error) {
+	switch c := clause.(type) {
+	case *ast.PartitionDefinitionClauseLessThan:
+		exprs, err := restoreExprs(c.Exprs)
+		if err != nil {
+			return "", err
+		}
+		return "LESS THAN (" + exprs + ")", nil
+	case *ast.PartitionDefinitionClauseIn:
+		if len(c.Values) == 0 {
+			return "DEFAULT", nil
+		}
+
+		values := make([]string, len(c.Values))
+		for i, v := range c.Values {
+			exprs, err := restoreExprs(v)
+			if err != nil {
+				return "", err
+			}
+			if len(v) > 1 {
+				exprs = "(" + exprs + ")"
+			}
+			values[i] = exprs
+		}
+		return "IN (" + strings.Join(values, ", ") + ")", nil
+	default:
+		return "", nil
+	}
+}
+
+// restoreExprs returns the SQL text of the given expressions, separated by commas
+func restoreExprs(exprs []ast.ExprNode) (string, error) {
+	ret := make([]string, len(exprs))
+	for i, expr := range exprs {
+		e, err := restoreExpr(expr)
+		if err != nil {
+			return "", err
+		}
+		ret[i] = e
+	}
+
+	return strings.Join(ret, ", "), nil
+}
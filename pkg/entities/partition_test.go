@@ -0,0 +1,258 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	_ "github.com/pingcap/tidb/pkg/parser/test_driver"
+)
+
+func lessThan(name string, values ...ast.ExprNode) *ast.PartitionDefinition {
+	return &ast.PartitionDefinition{
+		Name:   model.NewCIStr(name),
+		Clause: &ast.PartitionDefinitionClauseLessThan{Exprs: values},
+	}
+}
+
+func in(name string, values ...[]ast.ExprNode) *ast.PartitionDefinition {
+	return &ast.PartitionDefinition{
+		Name:   model.NewCIStr(name),
+		Clause: &ast.PartitionDefinitionClauseIn{Values: values},
+	}
+}
+
+func value(v any) ast.ExprNode {
+	return ast.NewValueExpr(v, "", "")
+}
+
+func partitionedTable(opts *ast.PartitionOptions) *ast.CreateTableStmt {
+	ct := createTable("events",
+		[]*ast.ColumnDef{intColumn("id", ast.ColumnOptionNotNull), intColumn("region", ast.ColumnOptionNotNull), intColumn("yr", ast.ColumnOptionNotNull)},
+		primaryKey("id", "region", "yr"),
+	)
+	ct.Partition = opts
+	return ct
+}
+
+func TestPartitioning(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *ast.PartitionOptions
+		want *Partitioning
+	}{
+		{
+			name: "range",
+			opts: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeRange, Expr: columnExpr("", "yr")},
+				Definitions: []*ast.PartitionDefinition{
+					lessThan("p2023", value(2024)),
+					{
+						Name:    model.NewCIStr("p2024"),
+						Clause:  &ast.PartitionDefinitionClauseLessThan{Exprs: []ast.ExprNode{value(2025)}},
+						Options: []*ast.TableOption{{Tp: ast.TableOptionComment, StrValue: "current year"}},
+					},
+					lessThan("pmax", &ast.MaxValueExpr{}),
+				},
+			},
+			want: &Partitioning{
+				Type:       "RANGE",
+				Expression: "`yr`",
+				Count:      3,
+				Definitions: []PartitionDefinition{
+					{Name: "p2023", Values: "LESS THAN (2024)"},
+					{Name: "p2024", Values: "LESS THAN (2025)", Comment: "current year"},
+					{Name: "pmax", Values: "LESS THAN (MAXVALUE)"},
+				},
+			},
+		},
+		{
+			name: "range columns",
+			opts: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeRange, ColumnNames: []*ast.ColumnName{columnName("yr"), columnName("region")}},
+				Definitions: []*ast.PartitionDefinition{
+					lessThan("p0", value(2024), value(10)),
+					lessThan("p1", &ast.MaxValueExpr{}, &ast.MaxValueExpr{}),
+				},
+			},
+			want: &Partitioning{
+				Type:    "RANGE",
+				Columns: []string{"yr", "region"},
+				Count:   2,
+				Definitions: []PartitionDefinition{
+					{Name: "p0", Values: "LESS THAN (2024, 10)"},
+					{Name: "p1", Values: "LESS THAN (MAXVALUE, MAXVALUE)"},
+				},
+			},
+		},
+		{
+			name: "list",
+			opts: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeList, Expr: columnExpr("", "region")},
+				Definitions: []*ast.PartitionDefinition{
+					in("p_north", []ast.ExprNode{value(1)}, []ast.ExprNode{value(2)}),
+					in("p_south", []ast.ExprNode{value(3)}),
+					in("p_other"),
+				},
+			},
+			want: &Partitioning{
+				Type:       "LIST",
+				Expression: "`region`",
+				Count:      3,
+				Definitions: []PartitionDefinition{
+					{Name: "p_north", Values: "IN (1, 2)"},
+					{Name: "p_south", Values: "IN (3)"},
+					{Name: "p_other", Values: "DEFAULT"},
+				},
+			},
+		},
+		{
+			name: "list columns",
+			opts: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeList, ColumnNames: []*ast.ColumnName{columnName("region"), columnName("yr")}},
+				Definitions: []*ast.PartitionDefinition{
+					in("p0", []ast.ExprNode{value(1), value(2024)}, []ast.ExprNode{value(2), value(2024)}),
+				},
+			},
+			want: &Partitioning{
+				Type:    "LIST",
+				Columns: []string{"region", "yr"},
+				Count:   1,
+				Definitions: []PartitionDefinition{
+					{Name: "p0", Values: "IN ((1, 2024), (2, 2024))"},
+				},
+			},
+		},
+		{
+			name: "hash",
+			opts: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeHash, Expr: columnExpr("", "id"), Num: 4},
+			},
+			want: &Partitioning{
+				Type:       "HASH",
+				Expression: "`id`",
+				Count:      4,
+			},
+		},
+		{
+			name: "linear key",
+			opts: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeKey, Linear: true, ColumnNames: []*ast.ColumnName{columnName("id")}, Num: 8},
+			},
+			want: &Partitioning{
+				Type:    "KEY",
+				Linear:  true,
+				Columns: []string{"id"},
+				Count:   8,
+			},
+		},
+		{
+			name: "subpartitions",
+			opts: &ast.PartitionOptions{
+				PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeRange, Expr: columnExpr("", "yr")},
+				Sub:             &ast.PartitionMethod{Tp: model.PartitionTypeHash, Expr: columnExpr("", "region"), Num: 2},
+				Definitions: []*ast.PartitionDefinition{
+					lessThan("p0", value(2024)),
+				},
+			},
+			want: &Partitioning{
+				Type:       "RANGE",
+				Expression: "`yr`",
+				Count:      1,
+				Definitions: []PartitionDefinition{
+					{Name: "p0", Values: "LESS THAN (2024)"},
+				},
+				SubPartitioning: &Partitioning{
+					Type:       "HASH",
+					Expression: "`region`",
+					Count:      2,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := NewTable(partitionedTable(tt.opts))
+			if err != nil {
+				t.Fatalf("NewTable() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(table.Partitioning, tt.want) {
+				t.Errorf("NewTable() partitioning = %+v, want %+v", table.Partitioning, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterPartitioning(t *testing.T) {
+	byYear := &ast.PartitionOptions{
+		PartitionMethod: ast.PartitionMethod{Tp: model.PartitionTypeRange, Expr: columnExpr("", "yr")},
+		Definitions: []*ast.PartitionDefinition{
+			lessThan("p2023", value(2024)),
+			lessThan("p2024", value(2025)),
+		},
+	}
+
+	s := NewSchema()
+	steps := []struct {
+		spec *ast.AlterTableSpec
+		want []string
+	}{
+		{
+			spec: &ast.AlterTableSpec{Tp: ast.AlterTablePartition, Partition: byYear},
+			want: []string{"p2023", "p2024"},
+		},
+		{
+			spec: &ast.AlterTableSpec{Tp: ast.AlterTableAddPartitions, PartDefinitions: []*ast.PartitionDefinition{lessThan("pmax", &ast.MaxValueExpr{})}},
+			want: []string{"p2023", "p2024", "pmax"},
+		},
+		{
+			spec: &ast.AlterTableSpec{Tp: ast.AlterTableDropPartition, PartitionNames: []model.CIStr{model.NewCIStr("P2023")}},
+			want: []string{"p2024", "pmax"},
+		},
+		{
+			spec: &ast.AlterTableSpec{Tp: ast.AlterTableRemovePartitioning},
+		},
+	}
+
+	if err := s.Apply(partitionedTable(nil)); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	table, _ := s.Table("events")
+
+	for i, step := range steps {
+		if err := s.Apply(alterTable("events", step.spec)); err != nil {
+			t.Fatalf("Apply() step %d error = %v", i, err)
+		}
+
+		if step.want == nil {
+			if table.Partitioning != nil {
+				t.Errorf("step %d: partitioning = %+v, want nil", i, table.Partitioning)
+			}
+			continue
+		}
+
+		names := make([]string, len(table.Partitioning.Definitions))
+		for j, d := range table.Partitioning.Definitions {
+			names[j] = d.Name
+		}
+		if !reflect.DeepEqual(names, step.want) {
+			t.Errorf("step %d: partitions = %v, want %v", i, names, step.want)
+		}
+		if table.Partitioning.Count != uint64(len(step.want)) {
+			t.Errorf("step %d: count = %d, want %d", i, table.Partitioning.Count, len(step.want))
+		}
+	}
+
+	errSpecs := []*ast.AlterTableSpec{
+		{Tp: ast.AlterTableAddPartitions, PartDefinitions: []*ast.PartitionDefinition{lessThan("p0", value(1))}},
+		{Tp: ast.AlterTableDropPartition, PartitionNames: []model.CIStr{model.NewCIStr("p0")}},
+	}
+	for i, spec := range errSpecs {
+		if err := s.Apply(alterTable("events", spec)); err == nil {
+			t.Errorf("Apply() error spec %d on an unpartitioned table = nil, want an error", i)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+var (
+	// ErrUnsupportedStatement is returned when a statement cannot be applied to the schema.
+	ErrUnsupportedStatement = errors.New("unsupported statement")
+
+	// ErrTableNotFound is returned when a statement refers to a table that does not exist.
+	ErrTableNotFound = errors.New("table does not exist")
+
+	// ErrTableExists is returned when a table is created with the name of an existing table.
+	ErrTableExists = errors.New("table already exists")
+)
+
+// Schema represents a set of MySQL tables that DDL statements can be applied to
+type Schema struct {
+	tables []*Table
+	byName map[string]*Table
+
+	// viewColumns holds the annotated view column types, keyed by lowercase view and column name.
+	viewColumns map[string]map[string]*Column
+}
+
+// NewSchema returns an empty Schema
+func NewSchema() *Schema {
+	return &Schema{
+		tables:      make([]*Table, 0),
+		byName:      make(map[string]*Table),
+		viewColumns: make(map[string]map[string]*Column),
+	}
+}
+
+// Tables returns the tables in the schema, in the order they were created
+func (s *Schema) Tables() []*Table {
+	return s.tables
+}
+
+// Table returns the named table, if it exists
+func (s *Schema) Table(name string) (*Table, bool) {
+	t, ok := s.byName[strings.ToLower(name)]
+	return t, ok
+}
+
+// AnnotateViewColumn sets the type of a view column, used when the type cannot be inferred from the tables
+// the view selects from. Annotations must be made before the view is created.
+func (s *Schema) AnnotateViewColumn(view string, def *ast.ColumnDef) error {
+	col, err := newColumn(def)
+	if err != nil {
+		return fmt.Errorf("error parsing annotation for view column %s.%s: %w", view, def.Name.String(), err)
+	}
+
+	// Keys are meaningless on a view.
+	col.InPrimaryKey = false
+	col.InUniqueKey = false
+	col.AutoIncrementing = false
+
+	view = strings.ToLower(view)
+	if s.viewColumns[view] == nil {
+		s.viewColumns[view] = make(map[string]*Column)
+	}
+	s.viewColumns[view][col.Name] = col
+	return nil
+}
+
+// Apply applies the given statement to the schema. ErrUnsupportedStatement is returned for
+// statements that do not describe the structure of a table.
+func (s *Schema) Apply(stmt ast.StmtNode) error {
+	switch stmt := stmt.(type) {
+	case *ast.CreateTableStmt:
+		return s.createTable(stmt)
+	case *ast.AlterTableStmt:
+		return s.alterTable(stmt)
+	case *ast.RenameTableStmt:
+		for _, tt := range stmt.TableToTables {
+			if err := s.renameTable(tt.OldTable.Name.String(), tt.NewTable.Name.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *ast.DropTableStmt:
+		return s.dropTables(stmt)
+	case *ast.CreateViewStmt:
+		return s.createView(stmt)
+	case *ast.CreateIndexStmt:
+		return s.createIndex(stmt)
+	case *ast.DropIndexStmt:
+		t, ok := s.Table(stmt.Table.Name.String())
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrTableNotFound, stmt.Table.Name.String())
+		}
+		if err := t.dropIndex(stmt.IndexName); err != nil && !stmt.IfExists {
+			return fmt.Errorf("error dropping index from table %q: %w", t.Name, err)
+		}
+		return nil
+	default:
+		return ErrUnsupportedStatement
+	}
+}
+
+func (s *Schema) add(t *Table) {
+	s.tables = append(s.tables, t)
+	s.byName[strings.ToLower(t.Name)] = t
+}
+
+func (s *Schema) createTable(ct *ast.CreateTableStmt) error {
+	if _, ok := s.Table(ct.Table.Name.String()); ok {
+		if ct.IfNotExists {
+			return nil
+		}
+		return fmt.Errorf("%w: %q", ErrTableExists, ct.Table.Name.String())
+	}
+
+	if ct.ReferTable != nil {
+		// CREATE TABLE ... LIKE ...
+		src, ok := s.Table(ct.ReferTable.Name.String())
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrTableNotFound, ct.ReferTable.Name.String())
+		}
+		s.add(src.copyAs(ct.Table.Name.String()))
+		return nil
+	}
+
+	t, err := NewTable(ct)
+	if err != nil {
+		return fmt.Errorf("error creating table from statement: %w", err)
+	}
+
+	s.add(t)
+	return nil
+}
+
+func (s *Schema) createView(cv *ast.CreateViewStmt) error {
+	name := cv.ViewName.Name.String()
+	existing, exists := s.Table(name)
+	if exists && (!cv.OrReplace || !existing.IsView) {
+		return fmt.Errorf("%w: %q", ErrTableExists, name)
+	}
+
+	v, err := NewView(cv, s, s.viewColumns[strings.ToLower(name)])
+	if err != nil {
+		return fmt.Errorf("error creating view from statement: %w", err)
+	}
+
+	if exists {
+		// CREATE OR REPLACE VIEW keeps the original position of the view.
+		idx := slices.Index(s.tables, existing)
+		s.tables[idx] = v
+		s.byName[strings.ToLower(name)] = v
+		return nil
+	}
+
+	s.add(v)
+	return nil
+}
+
+func (s *Schema) alterTable(at *ast.AlterTableStmt) error {
+	t, ok := s.Table(at.Table.Name.String())
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrTableNotFound, at.Table.Name.String())
+	}
+	if t.IsView {
+		return fmt.Errorf("%q is a view and cannot be altered", t.Name)
+	}
+
+	for _, spec := range at.Specs {
+		if spec.Tp == ast.AlterTableRenameTable {
+			if err := s.renameTable(t.Name, spec.NewTable.Name.String()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := t.alter(spec); err != nil {
+			return fmt.Errorf("error altering table %q: %w", t.Name, err)
+		}
+
+		switch spec.Tp {
+		case ast.AlterTableRenameColumn:
+			s.renameReferencedColumn(t.Name, spec.OldColumnName.Name.L, spec.NewColumnName.Name.L)
+		case ast.AlterTableChangeColumn:
+			s.renameReferencedColumn(t.Name, spec.OldColumnName.Name.L, spec.NewColumns[0].Name.Name.L)
+		}
+	}
+
+	return nil
+}
+
+// renameReferencedColumn keeps the foreign keys of every table pointing at a renamed column of the given table
+func (s *Schema) renameReferencedColumn(table, oldName, newName string) {
+	if oldName == newName {
+		return
+	}
+
+	for _, other := range s.tables {
+		for _, c := range other.Constraints {
+			if !strings.EqualFold(c.ReferenceTable, table) {
+				continue
+			}
+			for col, ref := range c.References {
+				if ref == oldName {
+					c.References[col] = newName
+				}
+			}
+			for i, ref := range c.ReferenceColumns {
+				if ref == oldName {
+					c.ReferenceColumns[i] = newName
+				}
+			}
+		}
+	}
+}
+
+func (s *Schema) createIndex(ci *ast.CreateIndexStmt) error {
+	t, ok := s.Table(ci.Table.Name.String())
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrTableNotFound, ci.Table.Name.String())
+	}
+	if t.IsView {
+		return fmt.Errorf("%q is a view and cannot be indexed", t.Name)
+	}
+
+	con := &ast.Constraint{
+		Name:   ci.IndexName,
+		Keys:   ci.IndexPartSpecifications,
+		Option: ci.IndexOption,
+	}
+
+	switch ci.KeyType {
+	case ast.IndexKeyTypeUnique:
+		con.Tp = ast.ConstraintUniqIndex
+	case ast.IndexKeyTypeFullText:
+		con.Tp = ast.ConstraintFulltext
+	default:
+		con.Tp = ast.ConstraintIndex
+	}
+
+	return t.addConstraint(con)
+}
+
+func (s *Schema) renameTable(oldName, newName string) error {
+	t, ok := s.Table(oldName)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrTableNotFound, oldName)
+	}
+	if _, ok := s.Table(newName); ok {
+		return fmt.Errorf("%w: %q", ErrTableExists, newName)
+	}
+
+	delete(s.byName, strings.ToLower(t.Name))
+	t.Name = newName
+	s.byName[strings.ToLower(newName)] = t
+
+	// Keep the foreign keys pointing at the renamed table.
+	for _, other := range s.tables {
+		for i := range other.Constraints {
+			if strings.EqualFold(other.Constraints[i].ReferenceTable, oldName) {
+				other.Constraints[i].ReferenceTable = newName
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Schema) dropTables(dt *ast.DropTableStmt) error {
+	for _, tn := range dt.Tables {
+		t, ok := s.Table(tn.Name.String())
+		if ok && t.IsView != dt.IsView {
+			ok = false
+		}
+		if !ok {
+			if dt.IfExists {
+				continue
+			}
+			return fmt.Errorf("%w: %q", ErrTableNotFound, tn.Name.String())
+		}
+
+		delete(s.byName, strings.ToLower(t.Name))
+		s.tables = slices.DeleteFunc(s.tables, func(o *Table) bool { return o == t })
+	}
+
+	return nil
+}
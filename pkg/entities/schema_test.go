@@ -0,0 +1,355 @@
+package entities
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+)
+
+func usersTable() *ast.CreateTableStmt {
+	return createTable("users",
+		[]*ast.ColumnDef{intColumn("id", ast.ColumnOptionNotNull), varcharColumn("email"), varcharColumn("name")},
+		primaryKey("id"),
+		uniqueKey("uniq_email", "email"),
+	)
+}
+
+func postsTable() *ast.CreateTableStmt {
+	return createTable("posts",
+		[]*ast.ColumnDef{intColumn("id", ast.ColumnOptionNotNull), intColumn("user_id", ast.ColumnOptionNotNull)},
+		primaryKey("id"),
+		foreignKey("fk_posts_user", []string{"user_id"}, "users", "id"),
+	)
+}
+
+func TestSchemaApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		stmts []ast.StmtNode
+		want  []string
+	}{
+		{
+			name:  "create table",
+			stmts: []ast.StmtNode{usersTable()},
+			want: []string{
+				"users(id int pk, email varchar null uq, name varchar null) primary(id) uniq_email:unique_key(email)",
+			},
+		},
+		{
+			name: "inline primary key",
+			stmts: []ast.StmtNode{
+				createTable("tags", []*ast.ColumnDef{intColumn("id", ast.ColumnOptionPrimaryKey), varcharColumn("label", ast.ColumnOptionUniqKey)}),
+			},
+			want: []string{
+				"tags(id int pk, label varchar null uq) primary(id) label:unique_key(label)",
+			},
+		},
+		{
+			name: "primary key columns are not null",
+			stmts: []ast.StmtNode{
+				createTable("tags", []*ast.ColumnDef{intColumn("id"), varcharColumn("label")}, primaryKey("id", "label")),
+			},
+			want: []string{
+				"tags(id int pk, label varchar pk) primary(id, label)",
+			},
+		},
+		{
+			name: "create if not exists",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				&ast.CreateTableStmt{Table: tableName("users"), IfNotExists: true, Cols: []*ast.ColumnDef{intColumn("other")}},
+			},
+			want: []string{
+				"users(id int pk, email varchar null uq, name varchar null) primary(id) uniq_email:unique_key(email)",
+			},
+		},
+		{
+			name: "create like",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				postsTable(),
+				&ast.CreateTableStmt{Table: tableName("posts_archive"), ReferTable: tableName("posts")},
+			},
+			want: []string{
+				"users(id int pk, email varchar null uq, name varchar null) primary(id) uniq_email:unique_key(email)",
+				"posts(id int pk, user_id int) primary(id) fk_posts_user(user_id)->users(id|id)",
+				"posts_archive(id int pk, user_id int) primary(id)",
+			},
+		},
+		{
+			name: "add columns",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users",
+					&ast.AlterTableSpec{Tp: ast.AlterTableAddColumns, NewColumns: []*ast.ColumnDef{intColumn("age")}},
+					&ast.AlterTableSpec{
+						Tp:         ast.AlterTableAddColumns,
+						NewColumns: []*ast.ColumnDef{varcharColumn("slug", ast.ColumnOptionNotNull, ast.ColumnOptionUniqKey)},
+						Position:   &ast.ColumnPosition{Tp: ast.ColumnPositionAfter, RelativeColumn: columnName("id")},
+					},
+					&ast.AlterTableSpec{
+						Tp:         ast.AlterTableAddColumns,
+						NewColumns: []*ast.ColumnDef{intColumn("tenant_id", ast.ColumnOptionNotNull)},
+						Position:   &ast.ColumnPosition{Tp: ast.ColumnPositionFirst},
+					},
+				),
+			},
+			want: []string{
+				"users(tenant_id int, id int pk, slug varchar uq, email varchar null uq, name varchar null, age int null) primary(id) uniq_email:unique_key(email) slug:unique_key(slug)",
+			},
+		},
+		{
+			name: "drop column",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				postsTable(),
+				alterTable("users", &ast.AlterTableSpec{Tp: ast.AlterTableDropColumn, OldColumnName: columnName("email")}),
+				alterTable("posts", &ast.AlterTableSpec{Tp: ast.AlterTableDropColumn, OldColumnName: columnName("user_id")}),
+			},
+			want: []string{
+				"users(id int pk, name varchar null) primary(id)",
+				"posts(id int pk) primary(id)",
+			},
+		},
+		{
+			name: "modify column",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users",
+					&ast.AlterTableSpec{Tp: ast.AlterTableModifyColumn, NewColumns: []*ast.ColumnDef{varcharColumn("email", ast.ColumnOptionNotNull)}},
+					&ast.AlterTableSpec{
+						Tp:         ast.AlterTableModifyColumn,
+						NewColumns: []*ast.ColumnDef{intColumn("name")},
+						Position:   &ast.ColumnPosition{Tp: ast.ColumnPositionFirst},
+					},
+				),
+			},
+			want: []string{
+				"users(name int null, id int pk, email varchar uq) primary(id) uniq_email:unique_key(email)",
+			},
+		},
+		{
+			name: "modify column unique twice",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users",
+					&ast.AlterTableSpec{Tp: ast.AlterTableModifyColumn, NewColumns: []*ast.ColumnDef{varcharColumn("email", ast.ColumnOptionUniqKey)}},
+					&ast.AlterTableSpec{Tp: ast.AlterTableModifyColumn, NewColumns: []*ast.ColumnDef{varcharColumn("name", ast.ColumnOptionUniqKey)}},
+					&ast.AlterTableSpec{Tp: ast.AlterTableModifyColumn, NewColumns: []*ast.ColumnDef{varcharColumn("name", ast.ColumnOptionUniqKey)}},
+				),
+			},
+			want: []string{
+				"users(id int pk, email varchar null uq, name varchar null uq) primary(id) uniq_email:unique_key(email) name:unique_key(name)",
+			},
+		},
+		{
+			name: "change column",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				postsTable(),
+				alterTable("users", &ast.AlterTableSpec{
+					Tp:            ast.AlterTableChangeColumn,
+					OldColumnName: columnName("id"),
+					NewColumns:    []*ast.ColumnDef{columnDef("user_id", mysql.TypeLonglong, ast.ColumnOptionNotNull)},
+				}),
+			},
+			want: []string{
+				"users(user_id bigint pk, email varchar null uq, name varchar null) primary(user_id) uniq_email:unique_key(email)",
+				"posts(id int pk, user_id int) primary(id) fk_posts_user(user_id)->users(user_id|user_id)",
+			},
+		},
+		{
+			name: "rename column",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				postsTable(),
+				alterTable("users", &ast.AlterTableSpec{Tp: ast.AlterTableRenameColumn, OldColumnName: columnName("id"), NewColumnName: columnName("uid")}),
+				alterTable("posts", &ast.AlterTableSpec{Tp: ast.AlterTableRenameColumn, OldColumnName: columnName("user_id"), NewColumnName: columnName("author_id")}),
+			},
+			want: []string{
+				"users(uid int pk, email varchar null uq, name varchar null) primary(uid) uniq_email:unique_key(email)",
+				"posts(id int pk, author_id int) primary(id) fk_posts_user(author_id)->users(uid|uid)",
+			},
+		},
+		{
+			name: "rename self referenced column",
+			stmts: []ast.StmtNode{
+				createTable("categories",
+					[]*ast.ColumnDef{intColumn("id", ast.ColumnOptionPrimaryKey), intColumn("parent_id")},
+					foreignKey("fk_parent", []string{"parent_id"}, "categories", "id"),
+				),
+				alterTable("categories", &ast.AlterTableSpec{Tp: ast.AlterTableRenameColumn, OldColumnName: columnName("id"), NewColumnName: columnName("category_id")}),
+			},
+			want: []string{
+				"categories(category_id int pk, parent_id int null) primary(category_id) fk_parent(parent_id)->categories(category_id|category_id)",
+			},
+		},
+		{
+			name: "keys",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users",
+					&ast.AlterTableSpec{Tp: ast.AlterTableDropIndex, Name: "uniq_email"},
+					&ast.AlterTableSpec{Tp: ast.AlterTableAddConstraint, Constraint: uniqueKey("uniq_name", "name")},
+					&ast.AlterTableSpec{Tp: ast.AlterTableDropPrimaryKey},
+				),
+				&ast.CreateIndexStmt{IndexName: "idx_email", Table: tableName("users"), IndexPartSpecifications: keyParts("email")},
+				&ast.CreateIndexStmt{IndexName: "uniq_id", Table: tableName("users"), IndexPartSpecifications: keyParts("id"), KeyType: ast.IndexKeyTypeUnique},
+				alterTable("users", &ast.AlterTableSpec{Tp: ast.AlterTableRenameIndex, FromKey: model.NewCIStr("uniq_name"), ToKey: model.NewCIStr("uniq_user_name")}),
+			},
+			want: []string{
+				"users(id int uq, email varchar null, name varchar null uq) uniq_user_name:unique_key(name) idx_email:index(email) uniq_id:unique_index(id)",
+			},
+		},
+		{
+			name: "drop index",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				&ast.DropIndexStmt{IndexName: "uniq_email", Table: tableName("users")},
+				&ast.DropIndexStmt{IndexName: "missing", Table: tableName("users"), IfExists: true},
+			},
+			want: []string{
+				"users(id int pk, email varchar null, name varchar null) primary(id)",
+			},
+		},
+		{
+			name: "rename table",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				postsTable(),
+				&ast.RenameTableStmt{TableToTables: []*ast.TableToTable{{OldTable: tableName("users"), NewTable: tableName("accounts")}}},
+			},
+			want: []string{
+				"accounts(id int pk, email varchar null uq, name varchar null) primary(id) uniq_email:unique_key(email)",
+				"posts(id int pk, user_id int) primary(id) fk_posts_user(user_id)->accounts(id|id)",
+			},
+		},
+		{
+			name: "drop table",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				postsTable(),
+				&ast.DropTableStmt{Tables: []*ast.TableName{tableName("users")}},
+				&ast.DropTableStmt{Tables: []*ast.TableName{tableName("missing")}, IfExists: true},
+			},
+			want: []string{
+				"posts(id int pk, user_id int) primary(id) fk_posts_user(user_id)->users(id|id)",
+			},
+		},
+		{
+			name: "recreate dropped table",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				&ast.DropTableStmt{Tables: []*ast.TableName{tableName("users")}},
+				createTable("users", []*ast.ColumnDef{intColumn("id", ast.ColumnOptionPrimaryKey)}),
+			},
+			want: []string{
+				"users(id int pk) primary(id)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchema()
+			for i, stmt := range tt.stmts {
+				if err := s.Apply(stmt); err != nil {
+					t.Fatalf("Apply() statement %d error = %v", i, err)
+				}
+			}
+
+			got := describe(s)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Apply() tables =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		stmts []ast.StmtNode
+		err   error
+	}{
+		{
+			name:  "table exists",
+			stmts: []ast.StmtNode{usersTable(), usersTable()},
+		},
+		{
+			name:  "alter missing table",
+			stmts: []ast.StmtNode{alterTable("users", &ast.AlterTableSpec{Tp: ast.AlterTableDropColumn, OldColumnName: columnName("email")})},
+		},
+		{
+			name: "column exists",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users", &ast.AlterTableSpec{Tp: ast.AlterTableAddColumns, NewColumns: []*ast.ColumnDef{varcharColumn("email")}}),
+			},
+		},
+		{
+			name: "drop missing column",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users", &ast.AlterTableSpec{Tp: ast.AlterTableDropColumn, OldColumnName: columnName("age")}),
+			},
+		},
+		{
+			name: "rename to existing column",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users", &ast.AlterTableSpec{Tp: ast.AlterTableRenameColumn, OldColumnName: columnName("email"), NewColumnName: columnName("name")}),
+			},
+		},
+		{
+			name: "add after missing column",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				alterTable("users", &ast.AlterTableSpec{
+					Tp:         ast.AlterTableAddColumns,
+					NewColumns: []*ast.ColumnDef{intColumn("age")},
+					Position:   &ast.ColumnPosition{Tp: ast.ColumnPositionAfter, RelativeColumn: columnName("missing")},
+				}),
+			},
+		},
+		{
+			name:  "drop missing table",
+			stmts: []ast.StmtNode{&ast.DropTableStmt{Tables: []*ast.TableName{tableName("users")}}},
+		},
+		{
+			name: "drop missing index",
+			stmts: []ast.StmtNode{
+				usersTable(),
+				&ast.DropIndexStmt{IndexName: "missing", Table: tableName("users")},
+			},
+		},
+		{
+			name:  "unsupported statement",
+			stmts: []ast.StmtNode{&ast.InsertStmt{}},
+			err:   ErrUnsupportedStatement,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchema()
+			var err error
+			for _, stmt := range tt.stmts {
+				if err = s.Apply(stmt); err != nil {
+					break
+				}
+			}
+
+			if err == nil {
+				t.Fatal("Apply() error = nil, want an error")
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package entities
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/jacobbrewer1/goschema/pkg/logging"
 	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/format"
 )
 
 // Table represents a MySQL table definition
@@ -16,20 +22,130 @@ type Table struct {
 	PrimaryKey  *Key
 	Keys        []Key
 	Constraints []Constraint
+	Checks      []Check
 	Comment     string
+
+	// Annotations holds the `goschema:key=value` annotations read from the table comment. They are removed from
+	// Comment.
+	Annotations map[string]string
+
+	// Table options
+	Engine        string
+	Charset       string
+	Collation     string
+	RowFormat     string
+	AutoIncrement uint64
+	Partitioning  *Partitioning
+
+	// IsView is true when the table is a view, which is read-only.
+	IsView bool
 }
 
 func (t *Table) setColumns(ct *ast.CreateTableStmt) error {
 	for i, col := range ct.Cols {
-		t.Columns[i] = &Column{
-			Name: col.Name.String(),
+		c, err := newColumn(col)
+		if err != nil {
+			return err
 		}
-		t.Columns[i].setTypeInfo(col.Tp)
-		t.Columns[i].setFlags(col.Tp.GetFlag())
-		if err := t.Columns[i].setOptions(col); err != nil {
+		t.Columns[i] = c
+		t.colMap[c.Name] = c
+
+		if err := t.addColumnChecks(c, col); err != nil {
 			return err
 		}
-		t.colMap[t.Columns[i].Name] = t.Columns[i]
+	}
+
+	return nil
+}
+
+// Annotation returns the value of the given annotation, and whether the table has it.
+func (t *Table) Annotation(key string) (string, bool) {
+	v, ok := t.Annotations[key]
+	return v, ok
+}
+
+// Column returns the named column, if it exists
+func (t *Table) Column(name string) (*Column, bool) {
+	col, ok := t.colMap[strings.ToLower(name)]
+	return col, ok
+}
+
+// SoftDeleteColumn returns the named column if it can mark the rows of the table as soft deleted, which requires a
+// nullable DATETIME or TIMESTAMP column. Views have no soft delete column.
+func (t *Table) SoftDeleteColumn(name string) (*Column, bool) {
+	if t.IsView || name == "" {
+		return nil, false
+	}
+
+	for _, col := range t.Columns {
+		if !strings.EqualFold(col.Name, name) {
+			continue
+		}
+
+		switch strings.ToLower(col.Type) {
+		case "datetime", "timestamp":
+			if col.Nullable {
+				return col, true
+			}
+		}
+
+		return nil, false
+	}
+
+	return nil, false
+}
+
+// addColumnChecks adds the CHECK constraints declared on the column definition
+func (t *Table) addColumnChecks(col *Column, def *ast.ColumnDef) error {
+	for _, opt := range def.Options {
+		if opt.Tp != ast.ColumnOptionCheck {
+			continue
+		}
+
+		c, err := newCheck(opt.ConstraintName, opt.Expr, opt.Enforced)
+		if err != nil {
+			return fmt.Errorf("error reading check on column %q: %w", col.Name, err)
+		}
+		c.Column = col.Name
+		t.Checks = append(t.Checks, c)
+	}
+
+	return nil
+}
+
+// setColumnKeys creates the keys declared inline on the column definitions (e.g. `id INT PRIMARY KEY`)
+func (t *Table) setColumnKeys(cols ...*Column) {
+	for _, col := range cols {
+		switch {
+		case col.InPrimaryKey && t.PrimaryKey == nil:
+			t.PrimaryKey = &Key{Name: "primary", Type: "primary", Columns: []*Column{col}}
+		case col.InUniqueKey && !col.InPrimaryKey && !t.hasUniqueKey(col):
+			t.Keys = append(t.Keys, Key{Name: col.Name, Type: "unique_key", Columns: []*Column{col}})
+		}
+	}
+}
+
+// hasUniqueKey reports whether the table already has a unique key on the column alone
+func (t *Table) hasUniqueKey(col *Column) bool {
+	return slices.ContainsFunc(t.Keys, func(k Key) bool {
+		return strings.HasPrefix(k.Type, "unique") && len(k.Columns) == 1 && k.Columns[0] == col
+	})
+}
+
+func (t *Table) addConstraint(con *ast.Constraint) error {
+	switch con.Tp {
+	case ast.ConstraintForeignKey:
+		t.addForeignKeyConstraint(con)
+	case ast.ConstraintPrimaryKey:
+		t.setPrimaryKey(con)
+	case ast.ConstraintCheck:
+		c, err := newCheck(con.Name, con.Expr, con.Enforced)
+		if err != nil {
+			return fmt.Errorf("error reading check %q: %w", con.Name, err)
+		}
+		t.Checks = append(t.Checks, c)
+	default:
+		t.addKey(con)
 	}
 
 	return nil
@@ -77,7 +193,6 @@ func (t *Table) setPrimaryKey(con *ast.Constraint) {
 
 func (t *Table) addKey(con *ast.Constraint) {
 	k := Key{Name: con.Name}
-	var uniq bool
 	switch con.Tp {
 	case ast.ConstraintKey:
 		k.Type = "key"
@@ -85,13 +200,10 @@ func (t *Table) addKey(con *ast.Constraint) {
 		k.Type = "index"
 	case ast.ConstraintUniq:
 		k.Type = "unique"
-		uniq = true
 	case ast.ConstraintUniqKey:
 		k.Type = "unique_key"
-		uniq = true
 	case ast.ConstraintUniqIndex:
 		k.Type = "unique_index"
-		uniq = true
 	case ast.ConstraintFulltext:
 		k.Type = "fulltext"
 	default:
@@ -108,19 +220,63 @@ func (t *Table) addKey(con *ast.Constraint) {
 			log.Printf("warning: found index for invalid field %q\n", col.Column.String())
 			return
 		}
-
-		k.Columns[i].InUniqueKey = uniq
 	}
 
 	t.Keys = append(t.Keys, k)
+	t.refreshKeyFlags()
+}
+
+// refreshKeyFlags recalculates the key membership flags of every column from the table keys.
+func (t *Table) refreshKeyFlags() {
+	for _, col := range t.Columns {
+		col.InPrimaryKey = false
+		col.InUniqueKey = false
+	}
+
+	if t.PrimaryKey != nil {
+		for _, col := range t.PrimaryKey.Columns {
+			col.InPrimaryKey = true
+			col.InUniqueKey = true
+			col.Nullable = false
+		}
+	}
+
+	for _, k := range t.Keys {
+		if !strings.HasPrefix(k.Type, "unique") {
+			continue
+		}
+		for _, col := range k.Columns {
+			col.InUniqueKey = true
+		}
+	}
 }
 
 // setTableOptions sets the options for the table
-func (t *Table) setTableOptions(ct *ast.CreateTableStmt) error {
-	for _, opt := range ct.Options {
+func (t *Table) setTableOptions(opts []*ast.TableOption) error {
+	for _, opt := range opts {
 		switch opt.Tp {
 		case ast.TableOptionComment:
-			t.Comment = opt.StrValue
+			comment, annotations, err := parseAnnotations(opt.StrValue)
+			if err != nil {
+				return fmt.Errorf("table %s: %w", t.Name, err)
+			}
+			t.Comment = comment
+			t.Annotations = annotations
+		case ast.TableOptionEngine:
+			t.Engine = opt.StrValue
+		case ast.TableOptionCharset:
+			t.Charset = opt.StrValue
+		case ast.TableOptionCollate:
+			t.Collation = opt.StrValue
+		case ast.TableOptionAutoIncrement:
+			t.AutoIncrement = opt.UintValue
+		case ast.TableOptionRowFormat:
+			// The row format is only held as a constant, so take the name from the restored option.
+			sb := new(strings.Builder)
+			if err := opt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, sb)); err != nil {
+				return fmt.Errorf("error reading row format: %w", err)
+			}
+			_, t.RowFormat, _ = strings.Cut(sb.String(), "= ")
 		default:
 			// ignore
 		}
@@ -129,6 +285,410 @@ func (t *Table) setTableOptions(ct *ast.CreateTableStmt) error {
 	return nil
 }
 
+// setPartitioning sets the partitioning of the table
+func (t *Table) setPartitioning(opts *ast.PartitionOptions) error {
+	if opts == nil {
+		t.Partitioning = nil
+		return nil
+	}
+
+	p, err := newPartitioning(opts)
+	if err != nil {
+		return err
+	}
+
+	t.Partitioning = p
+	return nil
+}
+
+// addColumn adds a new column to the table at the given position
+func (t *Table) addColumn(def *ast.ColumnDef, pos *ast.ColumnPosition) error {
+	col, err := newColumn(def)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := t.colMap[col.Name]; ok {
+		return fmt.Errorf("column %q already exists", col.Name)
+	}
+
+	if err := t.insertColumn(col, pos); err != nil {
+		return err
+	}
+
+	if err := t.addColumnChecks(col, def); err != nil {
+		return err
+	}
+
+	t.setColumnKeys(col)
+	t.refreshKeyFlags()
+	return nil
+}
+
+// insertColumn places the column in the table at the given position
+func (t *Table) insertColumn(col *Column, pos *ast.ColumnPosition) error {
+	idx := len(t.Columns)
+	if pos != nil {
+		switch pos.Tp {
+		case ast.ColumnPositionFirst:
+			idx = 0
+		case ast.ColumnPositionAfter:
+			after := pos.RelativeColumn.Name.L
+			idx = slices.IndexFunc(t.Columns, func(c *Column) bool { return c.Name == after })
+			if idx == -1 {
+				return fmt.Errorf("column %q does not exist", after)
+			}
+			idx++
+		default:
+			// Append to the end
+		}
+	}
+
+	t.Columns = slices.Insert(t.Columns, idx, col)
+	t.colMap[col.Name] = col
+	return nil
+}
+
+// dropColumn removes the column from the table, and from any keys or constraints that use it
+func (t *Table) dropColumn(name string) error {
+	col, ok := t.colMap[name]
+	if !ok {
+		return fmt.Errorf("column %q does not exist", name)
+	}
+
+	t.Columns = slices.DeleteFunc(t.Columns, func(c *Column) bool { return c == col })
+	delete(t.colMap, name)
+
+	if t.PrimaryKey != nil {
+		t.PrimaryKey.Columns = slices.DeleteFunc(t.PrimaryKey.Columns, func(c *Column) bool { return c == col })
+		if len(t.PrimaryKey.Columns) == 0 {
+			t.PrimaryKey = nil
+		}
+	}
+
+	keys := t.Keys[:0]
+	for _, k := range t.Keys {
+		k.Columns = slices.DeleteFunc(k.Columns, func(c *Column) bool { return c == col })
+		if len(k.Columns) > 0 {
+			keys = append(keys, k)
+		}
+	}
+	t.Keys = keys
+
+	t.Constraints = slices.DeleteFunc(t.Constraints, func(c Constraint) bool {
+		_, ok := c.References[name]
+		return ok
+	})
+
+	t.Checks = slices.DeleteFunc(t.Checks, func(c Check) bool { return c.Column == name })
+
+	t.refreshKeyFlags()
+	return nil
+}
+
+// modifyColumn replaces the definition of an existing column. The column is renamed if the
+// new definition has a different name.
+func (t *Table) modifyColumn(oldName string, def *ast.ColumnDef, pos *ast.ColumnPosition) error {
+	existing, ok := t.colMap[oldName]
+	if !ok {
+		return fmt.Errorf("column %q does not exist", oldName)
+	}
+
+	col, err := newColumn(def)
+	if err != nil {
+		return err
+	}
+
+	if col.Name != oldName {
+		if _, ok := t.colMap[col.Name]; ok {
+			return fmt.Errorf("column %q already exists", col.Name)
+		}
+		t.renameConstraintColumn(oldName, col.Name)
+	}
+
+	// Update the column in place so that any keys referencing it are kept.
+	addPK := col.InPrimaryKey && t.PrimaryKey == nil
+	addUnique := col.InUniqueKey && !col.InPrimaryKey
+	*existing = *col
+	delete(t.colMap, oldName)
+	t.colMap[existing.Name] = existing
+
+	if pos != nil && pos.Tp != ast.ColumnPositionNone {
+		t.Columns = slices.DeleteFunc(t.Columns, func(c *Column) bool { return c == existing })
+		if err := t.insertColumn(existing, pos); err != nil {
+			return err
+		}
+	}
+
+	if err := t.addColumnChecks(existing, def); err != nil {
+		return err
+	}
+
+	if addPK || addUnique {
+		t.setColumnKeys(existing)
+	}
+	t.refreshKeyFlags()
+	return nil
+}
+
+// renameColumn renames an existing column
+func (t *Table) renameColumn(oldName, newName string) error {
+	col, ok := t.colMap[oldName]
+	if !ok {
+		return fmt.Errorf("column %q does not exist", oldName)
+	}
+	if _, ok := t.colMap[newName]; ok {
+		return fmt.Errorf("column %q already exists", newName)
+	}
+
+	col.Name = newName
+	delete(t.colMap, oldName)
+	t.colMap[newName] = col
+	t.renameConstraintColumn(oldName, newName)
+	return nil
+}
+
+// renameConstraintColumn updates the local column name of any foreign key constraint using it
+func (t *Table) renameConstraintColumn(oldName, newName string) {
+	for i, c := range t.Constraints {
+		if ref, ok := c.References[oldName]; ok {
+			delete(c.References, oldName)
+			c.References[newName] = ref
+		}
+		if j := slices.Index(c.Columns, oldName); j >= 0 {
+			t.Constraints[i].Columns[j] = newName
+		}
+	}
+
+	for i := range t.Checks {
+		if t.Checks[i].Column == oldName {
+			t.Checks[i].Column = newName
+		}
+	}
+}
+
+// alterColumnDefault sets or drops the default value of an existing column
+func (t *Table) alterColumnDefault(def *ast.ColumnDef) error {
+	col, ok := t.colMap[def.Name.Name.L]
+	if !ok {
+		return fmt.Errorf("column %q does not exist", def.Name.Name.L)
+	}
+
+	col.Default = nil
+	col.HasDefault = false
+	for _, opt := range def.Options {
+		if opt.Tp != ast.ColumnOptionDefaultValue {
+			continue
+		}
+
+		col.HasDefault = true
+		v, ok := opt.Expr.(ast.ValueExpr)
+		if !ok {
+			col.Default = opt.Expr
+			continue
+		}
+
+		if v == nil || v.GetValue() == nil {
+			continue
+		}
+
+		// The ALTER COLUMN definition has no type information, so it is borrowed from the existing column.
+		if err := col.setDefaultValue(col.fieldType, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropPrimaryKey removes the primary key from the table
+func (t *Table) dropPrimaryKey() {
+	t.PrimaryKey = nil
+	t.refreshKeyFlags()
+}
+
+// dropIndex removes the named index from the table
+func (t *Table) dropIndex(name string) error {
+	if strings.EqualFold(name, "primary") {
+		t.dropPrimaryKey()
+		return nil
+	}
+
+	idx := slices.IndexFunc(t.Keys, func(k Key) bool { return strings.EqualFold(k.Name, name) })
+	if idx == -1 {
+		return fmt.Errorf("index %q does not exist", name)
+	}
+
+	t.Keys = slices.Delete(t.Keys, idx, idx+1)
+	t.refreshKeyFlags()
+	return nil
+}
+
+// renameIndex renames the named index
+func (t *Table) renameIndex(from, to string) error {
+	idx := slices.IndexFunc(t.Keys, func(k Key) bool { return strings.EqualFold(k.Name, from) })
+	if idx == -1 {
+		return fmt.Errorf("index %q does not exist", from)
+	}
+
+	t.Keys[idx].Name = to
+	return nil
+}
+
+// dropForeignKey removes the named foreign key constraint from the table
+func (t *Table) dropForeignKey(name string) error {
+	idx := slices.IndexFunc(t.Constraints, func(c Constraint) bool { return strings.EqualFold(c.Name, name) })
+	if idx == -1 {
+		return fmt.Errorf("foreign key %q does not exist", name)
+	}
+
+	t.Constraints = slices.Delete(t.Constraints, idx, idx+1)
+	return nil
+}
+
+// dropCheck removes the named CHECK constraint
+func (t *Table) dropCheck(name string) error {
+	idx := slices.IndexFunc(t.Checks, func(c Check) bool { return strings.EqualFold(c.Name, name) })
+	if idx == -1 {
+		return fmt.Errorf("check %q does not exist", name)
+	}
+
+	t.Checks = slices.Delete(t.Checks, idx, idx+1)
+	return nil
+}
+
+// enforceCheck sets whether the named CHECK constraint is enforced
+func (t *Table) enforceCheck(name string, enforced bool) error {
+	idx := slices.IndexFunc(t.Checks, func(c Check) bool { return strings.EqualFold(c.Name, name) })
+	if idx == -1 {
+		return fmt.Errorf("check %q does not exist", name)
+	}
+
+	t.Checks[idx].Enforced = enforced
+	return nil
+}
+
+// copyAs returns a deep copy of the table with the given name. Foreign keys are not copied, matching
+// the behaviour of CREATE TABLE ... LIKE.
+func (t *Table) copyAs(name string) *Table {
+	cp := &Table{
+		Name:        name,
+		Columns:     make([]*Column, len(t.Columns)),
+		colMap:      make(map[string]*Column, len(t.Columns)),
+		Checks:      slices.Clone(t.Checks),
+		Comment:     t.Comment,
+		Annotations: maps.Clone(t.Annotations),
+
+		Engine:        t.Engine,
+		Charset:       t.Charset,
+		Collation:     t.Collation,
+		RowFormat:     t.RowFormat,
+		AutoIncrement: t.AutoIncrement,
+		Partitioning:  t.Partitioning.clone(),
+
+		IsView: t.IsView,
+	}
+
+	for i, col := range t.Columns {
+		c := *col
+		c.Elements = slices.Clone(col.Elements)
+		c.Annotations = maps.Clone(col.Annotations)
+		cp.Columns[i] = &c
+		cp.colMap[c.Name] = &c
+	}
+
+	copyKey := func(k Key) Key {
+		k.Columns = slices.Clone(k.Columns)
+		for i, col := range k.Columns {
+			k.Columns[i] = cp.colMap[col.Name]
+		}
+		return k
+	}
+
+	if t.PrimaryKey != nil {
+		pk := copyKey(*t.PrimaryKey)
+		cp.PrimaryKey = &pk
+	}
+
+	cp.Keys = make([]Key, len(t.Keys))
+	for i, k := range t.Keys {
+		cp.Keys[i] = copyKey(k)
+	}
+
+	return cp
+}
+
+// alter applies the specifications of an ALTER TABLE statement to the table. Renaming the table
+// is handled by the Schema as the table is keyed by name.
+func (t *Table) alter(spec *ast.AlterTableSpec) error {
+	switch spec.Tp {
+	case ast.AlterTableOption:
+		return t.setTableOptions(spec.Options)
+	case ast.AlterTableAddColumns:
+		for _, def := range spec.NewColumns {
+			if err := t.addColumn(def, spec.Position); err != nil {
+				return err
+			}
+		}
+		for _, con := range spec.NewConstraints {
+			if err := t.addConstraint(con); err != nil {
+				return err
+			}
+		}
+	case ast.AlterTableAddConstraint:
+		return t.addConstraint(spec.Constraint)
+	case ast.AlterTableDropColumn:
+		return t.dropColumn(spec.OldColumnName.Name.L)
+	case ast.AlterTableModifyColumn:
+		def := spec.NewColumns[0]
+		return t.modifyColumn(def.Name.Name.L, def, spec.Position)
+	case ast.AlterTableChangeColumn:
+		return t.modifyColumn(spec.OldColumnName.Name.L, spec.NewColumns[0], spec.Position)
+	case ast.AlterTableRenameColumn:
+		return t.renameColumn(spec.OldColumnName.Name.L, spec.NewColumnName.Name.L)
+	case ast.AlterTableAlterColumn:
+		return t.alterColumnDefault(spec.NewColumns[0])
+	case ast.AlterTableDropPrimaryKey:
+		t.dropPrimaryKey()
+	case ast.AlterTableDropIndex:
+		return t.dropIndex(spec.Name)
+	case ast.AlterTableRenameIndex:
+		return t.renameIndex(spec.FromKey.O, spec.ToKey.O)
+	case ast.AlterTableDropForeignKey:
+		return t.dropForeignKey(spec.Name)
+	case ast.AlterTablePartition:
+		return t.setPartitioning(spec.Partition)
+	case ast.AlterTableRemovePartitioning:
+		t.Partitioning = nil
+	case ast.AlterTableAddPartitions:
+		if t.Partitioning == nil {
+			return errors.New("table is not partitioned")
+		}
+		if err := t.Partitioning.addDefinitions(spec.PartDefinitions); err != nil {
+			return err
+		}
+		t.Partitioning.Count = uint64(len(t.Partitioning.Definitions))
+	case ast.AlterTableDropPartition:
+		if t.Partitioning == nil {
+			return errors.New("table is not partitioned")
+		}
+		names := make([]string, len(spec.PartitionNames))
+		for i, name := range spec.PartitionNames {
+			names[i] = name.O
+		}
+		return t.Partitioning.dropDefinitions(names)
+	case ast.AlterTableDropCheck:
+		return t.dropCheck(spec.Constraint.Name)
+	case ast.AlterTableAlterCheck:
+		return t.enforceCheck(spec.Constraint.Name, spec.Constraint.Enforced)
+	default:
+		// Everything else (locks, algorithms, partition maintenance, etc.) does not change the model.
+		slog.Debug("Ignoring alter table specification", slog.Int(logging.KeyType, int(spec.Tp)))
+	}
+
+	return nil
+}
+
 // NewTable returns a Table struct representing the result of a MySQL CREATE TABLE statement
 func NewTable(ct *ast.CreateTableStmt) (*Table, error) {
 	table := &Table{
@@ -137,7 +697,7 @@ func NewTable(ct *ast.CreateTableStmt) (*Table, error) {
 		colMap:  make(map[string]*Column, len(ct.Cols)),
 	}
 
-	if err := table.setTableOptions(ct); err != nil {
+	if err := table.setTableOptions(ct.Options); err != nil {
 		return nil, err
 	}
 
@@ -145,16 +705,19 @@ func NewTable(ct *ast.CreateTableStmt) (*Table, error) {
 		return nil, err
 	}
 
+	if err := table.setPartitioning(ct.Partition); err != nil {
+		return nil, err
+	}
+
+	table.setColumnKeys(table.Columns...)
+
 	for _, con := range ct.Constraints {
-		switch con.Tp {
-		case ast.ConstraintForeignKey:
-			table.addForeignKeyConstraint(con)
-		case ast.ConstraintPrimaryKey:
-			table.setPrimaryKey(con)
-		default:
-			table.addKey(con)
+		if err := table.addConstraint(con); err != nil {
+			return nil, err
 		}
 	}
 
+	table.refreshKeyFlags()
+
 	return table, nil
 }
@@ -0,0 +1,157 @@
+package entities
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+func TestTableOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []*ast.TableOption
+		want    Table
+		wantErr error
+	}{
+		{
+			name: "options",
+			opts: []*ast.TableOption{
+				{Tp: ast.TableOptionEngine, StrValue: "InnoDB"},
+				{Tp: ast.TableOptionCharset, StrValue: "utf8mb4"},
+				{Tp: ast.TableOptionCollate, StrValue: "utf8mb4_unicode_ci"},
+				{Tp: ast.TableOptionAutoIncrement, UintValue: 1000},
+				{Tp: ast.TableOptionRowFormat, UintValue: ast.RowFormatCompressed},
+				{Tp: ast.TableOptionComment, StrValue: "Events of the app"},
+			},
+			want: Table{
+				Engine:        "InnoDB",
+				Charset:       "utf8mb4",
+				Collation:     "utf8mb4_unicode_ci",
+				AutoIncrement: 1000,
+				RowFormat:     "COMPRESSED",
+				Comment:       "Events of the app",
+			},
+		},
+		{
+			name: "annotations",
+			opts: []*ast.TableOption{
+				{Tp: ast.TableOptionComment, StrValue: "Events of the app goschema:name=Event goschema:plural=EventLog"},
+			},
+			want: Table{
+				Comment:     "Events of the app",
+				Annotations: map[string]string{AnnotationName: "Event", AnnotationPlural: "EventLog"},
+			},
+		},
+		{
+			name: "invalid annotation",
+			opts: []*ast.TableOption{
+				{Tp: ast.TableOptionComment, StrValue: "goschema:name="},
+			},
+			wantErr: ErrInvalidAnnotation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := createTable("events", []*ast.ColumnDef{intColumn("id", ast.ColumnOptionPrimaryKey)})
+			ct.Options = tt.opts
+
+			got, err := NewTable(ct)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NewTable() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewTable() error = %v", err)
+			}
+
+			if got.Engine != tt.want.Engine || got.Charset != tt.want.Charset || got.Collation != tt.want.Collation {
+				t.Errorf("NewTable() engine, charset, collation = %q, %q, %q, want %q, %q, %q",
+					got.Engine, got.Charset, got.Collation, tt.want.Engine, tt.want.Charset, tt.want.Collation)
+			}
+			if got.AutoIncrement != tt.want.AutoIncrement {
+				t.Errorf("NewTable() auto increment = %d, want %d", got.AutoIncrement, tt.want.AutoIncrement)
+			}
+			if got.RowFormat != tt.want.RowFormat {
+				t.Errorf("NewTable() row format = %q, want %q", got.RowFormat, tt.want.RowFormat)
+			}
+			if got.Comment != tt.want.Comment {
+				t.Errorf("NewTable() comment = %q, want %q", got.Comment, tt.want.Comment)
+			}
+			if !maps.Equal(got.Annotations, tt.want.Annotations) {
+				t.Errorf("NewTable() annotations = %v, want %v", got.Annotations, tt.want.Annotations)
+			}
+		})
+	}
+}
+
+func TestAlterTableOptions(t *testing.T) {
+	s := NewSchema()
+	ct := createTable("events", []*ast.ColumnDef{intColumn("id", ast.ColumnOptionPrimaryKey)})
+	ct.Options = []*ast.TableOption{
+		{Tp: ast.TableOptionEngine, StrValue: "MyISAM"},
+		{Tp: ast.TableOptionComment, StrValue: "Events"},
+	}
+	if err := s.Apply(ct); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	err := s.Apply(alterTable("events", &ast.AlterTableSpec{
+		Tp: ast.AlterTableOption,
+		Options: []*ast.TableOption{
+			{Tp: ast.TableOptionEngine, StrValue: "InnoDB"},
+			{Tp: ast.TableOptionAutoIncrement, UintValue: 5},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	table, _ := s.Table("events")
+	if table.Engine != "InnoDB" || table.AutoIncrement != 5 || table.Comment != "Events" {
+		t.Errorf("Apply() engine, auto increment, comment = %q, %d, %q, want %q, %d, %q",
+			table.Engine, table.AutoIncrement, table.Comment, "InnoDB", 5, "Events")
+	}
+}
+
+func TestCopyAs(t *testing.T) {
+	comment := ast.NewValueExpr("goschema:name=Title", "", "")
+	comment.SetText(nil, "goschema:name=Title")
+	name := varcharColumn("name")
+	name.Options = append(name.Options, &ast.ColumnOption{Tp: ast.ColumnOptionComment, Expr: comment})
+	ct := createTable("events", []*ast.ColumnDef{intColumn("id", ast.ColumnOptionPrimaryKey, ast.ColumnOptionAutoIncrement), name}, uniqueKey("uniq_name", "name"))
+	ct.Options = []*ast.TableOption{
+		{Tp: ast.TableOptionEngine, StrValue: "InnoDB"},
+		{Tp: ast.TableOptionAutoIncrement, UintValue: 1000},
+		{Tp: ast.TableOptionRowFormat, UintValue: ast.RowFormatCompressed},
+		{Tp: ast.TableOptionComment, StrValue: "Events goschema:plural=EventLog"},
+	}
+	src, err := NewTable(ct)
+	if err != nil {
+		t.Fatalf("NewTable() error = %v", err)
+	}
+
+	got := src.copyAs("events_copy")
+
+	want := *src
+	want.Name = "events_copy"
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("copyAs() = %+v, want %+v", *got, want)
+	}
+
+	// The copy does not share anything that can be changed with the source.
+	got.Annotations[AnnotationPlural] = "Copies"
+	got.Columns[1].Annotations[AnnotationName] = "Other"
+	got.Columns[1].Elements = append(got.Columns[1].Elements, "x")
+	if src.Annotations[AnnotationPlural] != "EventLog" || src.Columns[1].Annotations[AnnotationName] != "Title" || len(src.Columns[1].Elements) != 0 {
+		t.Errorf("copyAs() shares state with the source table")
+	}
+	if got.Keys[0].Columns[0] != got.Columns[1] || got.PrimaryKey.Columns[0] != got.Columns[0] {
+		t.Errorf("copyAs() keys do not refer to the copied columns")
+	}
+}
@@ -0,0 +1,319 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+const (
+	// maxDecimalPrecision and maxDecimalScale are the largest precision and scale of a MySQL decimal.
+	maxDecimalPrecision = 65
+	maxDecimalScale     = 30
+)
+
+var (
+	// ErrUnresolvedViewColumn is returned when the type of a view column cannot be inferred and no annotation
+	// has been provided for it.
+	ErrUnresolvedViewColumn = errors.New("unable to infer the type of view column")
+)
+
+// viewSource is a table that a view selects from. The table is nil for derived tables, as their columns are unknown.
+type viewSource struct {
+	alias    string
+	table    *Table
+	nullable bool
+}
+
+// NewView returns a Table representing the result of a MySQL CREATE VIEW statement. Column types are inferred
+// from the tables the view selects from, falling back to the given annotations, keyed by lowercase column name.
+func NewView(cv *ast.CreateViewStmt, schema *Schema, annotations map[string]*Column) (*Table, error) {
+	view := &Table{
+		Name:   cv.ViewName.Name.String(),
+		IsView: true,
+		colMap: make(map[string]*Column),
+	}
+
+	sel, ok := firstSelect(cv.Select)
+	if !ok {
+		return nil, fmt.Errorf("view %q: unsupported select statement %T", view.Name, cv.Select)
+	}
+
+	var sources []viewSource
+	if sel.From != nil && sel.From.TableRefs != nil {
+		var err error
+		if sources, err = resolveSources(sel.From.TableRefs, schema, false); err != nil {
+			return nil, fmt.Errorf("view %q: %w", view.Name, err)
+		}
+	}
+
+	for _, field := range sel.Fields.Fields {
+		cols, err := resolveField(field, sources)
+		if err != nil {
+			return nil, fmt.Errorf("view %q: %w", view.Name, err)
+		}
+		view.Columns = append(view.Columns, cols...)
+	}
+
+	if len(cv.Cols) > 0 {
+		if len(cv.Cols) != len(view.Columns) {
+			return nil, fmt.Errorf("view %q: %d column names given for %d columns", view.Name, len(cv.Cols), len(view.Columns))
+		}
+		for i, name := range cv.Cols {
+			view.Columns[i].Name = name.L
+		}
+	}
+
+	for i, col := range view.Columns {
+		if col.Name == "" {
+			// MySQL would name the column after the expression, which cannot be used as a field name.
+			return nil, fmt.Errorf("view %q: column %d must be given an alias", view.Name, i+1)
+		}
+
+		if a, ok := annotations[col.Name]; ok {
+			c := *a
+			c.Name = col.Name
+			view.Columns[i] = &c
+			col = &c
+		}
+
+		if col.Type == "" {
+			return nil, fmt.Errorf("view %q: %w %q, add a view column annotation", view.Name, ErrUnresolvedViewColumn, col.Name)
+		}
+
+		view.colMap[col.Name] = col
+	}
+
+	return view, nil
+}
+
+// firstSelect returns the select statement that determines the columns of a view. For unions this is the
+// first select in the list.
+func firstSelect(node ast.StmtNode) (*ast.SelectStmt, bool) {
+	switch n := node.(type) {
+	case *ast.SelectStmt:
+		return n, true
+	case *ast.SetOprStmt:
+		if n.SelectList == nil || len(n.SelectList.Selects) == 0 {
+			return nil, false
+		}
+		first, ok := n.SelectList.Selects[0].(ast.StmtNode)
+		if !ok {
+			return nil, false
+		}
+		return firstSelect(first)
+	default:
+		return nil, false
+	}
+}
+
+// resolveSources returns the tables referenced by the join, in the order they appear. Tables on the optional side
+// of an outer join are marked as nullable. ErrTableNotFound is returned when a table is not in the schema.
+func resolveSources(node ast.ResultSetNode, schema *Schema, nullable bool) ([]viewSource, error) {
+	switch n := node.(type) {
+	case *ast.Join:
+		left, right := nullable, nullable
+		switch n.Tp {
+		case ast.LeftJoin:
+			right = true
+		case ast.RightJoin:
+			left = true
+		}
+
+		sources, err := resolveSources(n.Left, schema, left)
+		if err != nil {
+			return nil, err
+		}
+		if n.Right != nil {
+			right, err := resolveSources(n.Right, schema, right)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, right...)
+		}
+		return sources, nil
+	case *ast.TableSource:
+		src := viewSource{alias: n.AsName.L, nullable: nullable}
+		tn, ok := n.Source.(*ast.TableName)
+		if !ok {
+			// Derived tables cannot be resolved, so their columns need annotations.
+			return []viewSource{src}, nil
+		}
+
+		if src.alias == "" {
+			src.alias = tn.Name.L
+		}
+		if src.table, ok = schema.Table(tn.Name.String()); !ok {
+			return nil, fmt.Errorf("%w: %q", ErrTableNotFound, tn.Name.String())
+		}
+		return []viewSource{src}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// resolveField returns the view columns for the given select field. Columns that cannot be inferred are
+// returned without a type, and expressions without an alias are returned without a name.
+func resolveField(field *ast.SelectField, sources []viewSource) ([]*Column, error) {
+	if field.WildCard != nil {
+		cols := make([]*Column, 0)
+		for _, src := range sources {
+			if field.WildCard.Table.L != "" && field.WildCard.Table.L != src.alias {
+				continue
+			}
+			if src.table == nil {
+				// Skipping the source would silently leave its columns out of the view.
+				return nil, fmt.Errorf("unable to expand wildcard %q, the columns of %q are unknown", wildcardName(field.WildCard), src.alias)
+			}
+			for _, col := range src.table.Columns {
+				cols = append(cols, viewColumn(col, col.Name, src.nullable))
+			}
+		}
+		if len(cols) == 0 {
+			return nil, fmt.Errorf("unable to expand wildcard %q", wildcardName(field.WildCard))
+		}
+		return cols, nil
+	}
+
+	name := field.AsName.L
+	if cn, ok := field.Expr.(*ast.ColumnNameExpr); ok && name == "" {
+		name = cn.Name.Name.L
+	}
+
+	switch expr := field.Expr.(type) {
+	case *ast.ColumnNameExpr:
+		if col, nullable, ok := findColumn(expr.Name, sources); ok {
+			return []*Column{viewColumn(col, name, nullable)}, nil
+		}
+	case *ast.AggregateFuncExpr:
+		if col := aggregateColumn(expr, name, sources); col != nil {
+			return []*Column{col}, nil
+		}
+	}
+
+	return []*Column{{Name: name, Nullable: true}}, nil
+}
+
+// wildcardName returns the wildcard as written in the select, e.g. `u.*`
+func wildcardName(w *ast.WildCardField) string {
+	if w.Table.O == "" {
+		return "*"
+	}
+	return w.Table.O + ".*"
+}
+
+// findColumn finds the source column referenced by the given name
+func findColumn(name *ast.ColumnName, sources []viewSource) (*Column, bool, bool) {
+	for _, src := range sources {
+		if src.table == nil || (name.Table.L != "" && name.Table.L != src.alias) {
+			continue
+		}
+		if col, ok := src.table.colMap[name.Name.L]; ok {
+			return col, src.nullable, true
+		}
+	}
+
+	return nil, false, false
+}
+
+// aggregateColumn infers the column produced by an aggregate function, if possible
+func aggregateColumn(expr *ast.AggregateFuncExpr, name string, sources []viewSource) *Column {
+	if strings.EqualFold(expr.F, ast.AggFuncCount) {
+		return &Column{Name: name, Type: "bigint"}
+	}
+
+	if len(expr.Args) != 1 {
+		return nil
+	}
+	cn, ok := expr.Args[0].(*ast.ColumnNameExpr)
+	if !ok {
+		return nil
+	}
+	col, _, ok := findColumn(cn.Name, sources)
+	if !ok {
+		return nil
+	}
+
+	// Aggregates other than COUNT return NULL when there are no rows.
+	switch strings.ToLower(expr.F) {
+	case ast.AggFuncSum, ast.AggFuncAvg:
+		return sumColumn(col, name, strings.EqualFold(expr.F, ast.AggFuncAvg))
+	case ast.AggFuncMin, ast.AggFuncMax:
+		return viewColumn(col, name, true)
+	default:
+		return nil
+	}
+}
+
+// sumColumn returns the column produced by SUM or AVG of the given column. As in MySQL, exact values give a decimal
+// wide enough for the sum, and approximate values give a double.
+func sumColumn(col *Column, name string, avg bool) *Column {
+	if col.Type == "float" || col.Type == "double" {
+		return &Column{Name: name, Type: "double", Nullable: true}
+	}
+
+	precision, scale, ok := decimalDigits(col)
+	if !ok {
+		return nil
+	}
+	if avg {
+		precision, scale = precision+4, scale+4
+	} else {
+		precision += 22
+	}
+
+	return &Column{
+		Name:          name,
+		Type:          "decimal",
+		TypeSize:      min(precision, maxDecimalPrecision),
+		TypePrecision: min(scale, maxDecimalScale),
+		Nullable:      true,
+	}
+}
+
+// decimalDigits returns the precision and scale of the exact value column
+func decimalDigits(col *Column) (int, int, bool) {
+	switch col.Type {
+	case "tinyint":
+		return 3, 0, true
+	case "smallint":
+		return 5, 0, true
+	case "mediumint":
+		return 8, 0, true
+	case "int":
+		return 10, 0, true
+	case "bigint":
+		if col.Unsigned {
+			return 20, 0, true
+		}
+		return 19, 0, true
+	case "decimal":
+		// MySQL defaults to DECIMAL(10, 0) when the precision is not given.
+		precision, scale := col.TypeSize, max(col.TypePrecision, 0)
+		if precision <= 0 {
+			precision = 10
+		}
+		return precision, scale, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// viewColumn returns a copy of the source column for use in a view, linked to the table column it is selected from.
+// Keys and defaults do not carry over to views.
+func viewColumn(src *Column, name string, nullable bool) *Column {
+	c := *src
+	if src.Source == nil {
+		c.Source = src
+	}
+	c.Name = name
+	c.Nullable = src.Nullable || nullable
+	c.InPrimaryKey = false
+	c.InUniqueKey = false
+	c.AutoIncrementing = false
+	c.HasDefault = false
+	c.Default = nil
+	return &c
+}
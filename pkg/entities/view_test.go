@@ -0,0 +1,280 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+)
+
+func tableSource(name, alias string) *ast.TableSource {
+	return &ast.TableSource{Source: tableName(name), AsName: model.NewCIStr(alias)}
+}
+
+func derivedTable(alias string) *ast.TableSource {
+	return &ast.TableSource{Source: &ast.SelectStmt{}, AsName: model.NewCIStr(alias)}
+}
+
+func join(tp ast.JoinType, left, right ast.ResultSetNode) *ast.Join {
+	return &ast.Join{Left: left, Right: right, Tp: tp}
+}
+
+func columnExpr(table, name string) *ast.ColumnNameExpr {
+	return &ast.ColumnNameExpr{Name: &ast.ColumnName{Table: model.NewCIStr(table), Name: model.NewCIStr(name)}}
+}
+
+func field(expr ast.ExprNode, alias string) *ast.SelectField {
+	return &ast.SelectField{Expr: expr, AsName: model.NewCIStr(alias)}
+}
+
+func wildcard(table string) *ast.SelectField {
+	return &ast.SelectField{WildCard: &ast.WildCardField{Table: model.NewCIStr(table)}}
+}
+
+func aggregate(f string, args ...ast.ExprNode) *ast.AggregateFuncExpr {
+	return &ast.AggregateFuncExpr{F: f, Args: args}
+}
+
+func createView(name string, from ast.ResultSetNode, fields ...*ast.SelectField) *ast.CreateViewStmt {
+	if _, ok := from.(*ast.Join); !ok {
+		from = &ast.Join{Left: from}
+	}
+
+	return &ast.CreateViewStmt{
+		ViewName: tableName(name),
+		Select: &ast.SelectStmt{
+			From:   &ast.TableRefsClause{TableRefs: from.(*ast.Join)},
+			Fields: &ast.FieldList{Fields: fields},
+		},
+	}
+}
+
+func TestNewView(t *testing.T) {
+	tests := []struct {
+		name        string
+		view        *ast.CreateViewStmt
+		annotations []*ast.ColumnDef
+		want        string
+	}{
+		{
+			name: "columns",
+			view: createView("v", tableSource("users", "u"), field(columnExpr("u", "id"), "user_id"), field(columnExpr("", "email"), "")),
+			want: "v(user_id int, email varchar null)",
+		},
+		{
+			name: "column names",
+			view: func() *ast.CreateViewStmt {
+				cv := createView("v", tableSource("users", ""), field(columnExpr("users", "id"), ""), field(columnExpr("users", "name"), ""))
+				cv.Cols = []model.CIStr{model.NewCIStr("uid"), model.NewCIStr("full_name")}
+				return cv
+			}(),
+			want: "v(uid int, full_name varchar null)",
+		},
+		{
+			name: "inner join",
+			view: createView("v",
+				join(ast.CrossJoin, tableSource("users", "u"), tableSource("posts", "p")),
+				field(columnExpr("u", "id"), ""),
+				field(columnExpr("p", "id"), "post_id"),
+			),
+			want: "v(id int, post_id int)",
+		},
+		{
+			name: "left join",
+			view: createView("v",
+				join(ast.LeftJoin, tableSource("users", "u"), tableSource("posts", "p")),
+				field(columnExpr("u", "id"), ""),
+				field(columnExpr("p", "id"), "post_id"),
+			),
+			want: "v(id int, post_id int null)",
+		},
+		{
+			name: "right join",
+			view: createView("v",
+				join(ast.RightJoin, tableSource("users", "u"), tableSource("posts", "p")),
+				field(columnExpr("u", "id"), ""),
+				field(columnExpr("p", "id"), "post_id"),
+			),
+			want: "v(id int null, post_id int)",
+		},
+		{
+			name: "wildcard",
+			view: createView("v", tableSource("users", ""), wildcard("")),
+			want: "v(id int, email varchar null, name varchar null)",
+		},
+		{
+			name: "qualified wildcard",
+			view: createView("v",
+				join(ast.LeftJoin, tableSource("users", "u"), tableSource("posts", "p")),
+				wildcard("p"),
+				field(columnExpr("u", "name"), ""),
+			),
+			want: "v(id int null, user_id int null, name varchar null)",
+		},
+		{
+			name: "aggregates",
+			view: createView("v",
+				join(ast.LeftJoin, tableSource("users", "u"), tableSource("posts", "p")),
+				field(columnExpr("u", "id"), ""),
+				field(aggregate(ast.AggFuncCount, columnExpr("p", "id")), "post_count"),
+				field(aggregate(ast.AggFuncMax, columnExpr("p", "id")), "last_post_id"),
+				field(aggregate(ast.AggFuncSum, columnExpr("p", "user_id")), "total"),
+			),
+			want: "v(id int, post_count bigint, last_post_id int null, total decimal null)",
+		},
+		{
+			name:        "annotated derived table",
+			view:        createView("v", join(ast.CrossJoin, tableSource("users", "u"), derivedTable("d")), field(columnExpr("u", "id"), ""), field(columnExpr("d", "total"), "")),
+			annotations: []*ast.ColumnDef{columnDef("total", mysql.TypeNewDecimal, ast.ColumnOptionNotNull)},
+			want:        "v(id int, total decimal)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchema()
+			for _, stmt := range []ast.StmtNode{usersTable(), postsTable()} {
+				if err := s.Apply(stmt); err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+			}
+			for _, def := range tt.annotations {
+				if err := s.AnnotateViewColumn(tt.view.ViewName.Name.O, def); err != nil {
+					t.Fatalf("AnnotateViewColumn() error = %v", err)
+				}
+			}
+
+			if err := s.Apply(tt.view); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			v, ok := s.Table(tt.view.ViewName.Name.O)
+			if !ok || !v.IsView {
+				t.Fatalf("Table() = %v, want a view", v)
+			}
+			if got := describe(s); got[len(got)-1] != tt.want {
+				t.Errorf("NewView() = %q, want %q", got[len(got)-1], tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregatePrecision(t *testing.T) {
+	amount := columnDef("amount", mysql.TypeNewDecimal)
+	amount.Tp.SetFlen(10)
+	amount.Tp.SetDecimal(2)
+	wide := columnDef("wide", mysql.TypeNewDecimal)
+	wide.Tp.SetFlen(60)
+	wide.Tp.SetDecimal(28)
+	orders := createTable("orders", []*ast.ColumnDef{
+		intColumn("qty"), columnDef("total", mysql.TypeLonglong), amount, wide, columnDef("ratio", mysql.TypeDouble),
+	})
+
+	tests := []struct {
+		name      string
+		agg       *ast.AggregateFuncExpr
+		want      string
+		precision int
+		scale     int
+	}{
+		{name: "sum int", agg: aggregate(ast.AggFuncSum, columnExpr("", "qty")), want: "decimal", precision: 32},
+		{name: "sum bigint", agg: aggregate(ast.AggFuncSum, columnExpr("", "total")), want: "decimal", precision: 41},
+		{name: "sum decimal", agg: aggregate(ast.AggFuncSum, columnExpr("", "amount")), want: "decimal", precision: 32, scale: 2},
+		{name: "sum wide decimal", agg: aggregate(ast.AggFuncSum, columnExpr("", "wide")), want: "decimal", precision: 65, scale: 28},
+		{name: "avg int", agg: aggregate(ast.AggFuncAvg, columnExpr("", "qty")), want: "decimal", precision: 14, scale: 4},
+		{name: "avg decimal", agg: aggregate(ast.AggFuncAvg, columnExpr("", "amount")), want: "decimal", precision: 14, scale: 6},
+		{name: "avg wide decimal", agg: aggregate(ast.AggFuncAvg, columnExpr("", "wide")), want: "decimal", precision: 64, scale: 30},
+		{name: "sum double", agg: aggregate(ast.AggFuncSum, columnExpr("", "ratio")), want: "double"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchema()
+			if err := s.Apply(orders); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if err := s.Apply(createView("v", tableSource("orders", ""), field(tt.agg, "agg"))); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			v, _ := s.Table("v")
+			col := v.Columns[0]
+			if col.Type != tt.want || col.TypeSize != tt.precision || col.TypePrecision != tt.scale || !col.Nullable {
+				t.Errorf("NewView() column = %s(%d, %d) null %t, want %s(%d, %d) null", col.Type, col.TypeSize,
+					col.TypePrecision, col.Nullable, tt.want, tt.precision, tt.scale)
+			}
+		})
+	}
+}
+
+func TestNewViewErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		view *ast.CreateViewStmt
+		err  error
+	}{
+		{
+			name: "unresolved column",
+			view: createView("v", derivedTable("d"), field(columnExpr("d", "total"), "")),
+			err:  ErrUnresolvedViewColumn,
+		},
+		{
+			name: "unresolved expression",
+			view: createView("v", tableSource("users", ""), field(aggregate(ast.AggFuncGroupConcat, columnExpr("", "name")), "names")),
+			err:  ErrUnresolvedViewColumn,
+		},
+		{
+			name: "sum of text",
+			view: createView("v", tableSource("users", ""), field(aggregate(ast.AggFuncSum, columnExpr("", "name")), "total")),
+			err:  ErrUnresolvedViewColumn,
+		},
+		{
+			name: "missing alias",
+			view: createView("v", tableSource("users", ""), field(aggregate(ast.AggFuncCount, columnExpr("", "id")), "")),
+		},
+		{
+			name: "wildcard over derived table",
+			view: createView("v", join(ast.CrossJoin, tableSource("users", "u"), derivedTable("d")), wildcard("d")),
+		},
+		{
+			name: "wildcard over join with derived table",
+			view: createView("v", join(ast.CrossJoin, tableSource("users", "u"), derivedTable("d")), wildcard("")),
+		},
+		{
+			name: "unknown table",
+			view: createView("v", tableSource("orders", ""), field(columnExpr("", "id"), "")),
+			err:  ErrTableNotFound,
+		},
+		{
+			name: "unknown joined table",
+			view: createView("v", join(ast.LeftJoin, tableSource("users", "u"), tableSource("orders", "o")), field(columnExpr("u", "id"), "")),
+			err:  ErrTableNotFound,
+		},
+		{
+			name: "wildcard over unknown alias",
+			view: createView("v", tableSource("users", "u"), wildcard("x")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchema()
+			if err := s.Apply(usersTable()); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			err := s.Apply(tt.view)
+			if err == nil {
+				t.Fatal("Apply() error = nil, want an error")
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Apply() error = %v, want %v", err, tt.err)
+			}
+			if _, ok := s.Table("v"); ok {
+				t.Error("Table() found the view, want it not created")
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package generation
+
+import (
+	"strings"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// AuditTableName is the name of the table that the audited writes are recorded in.
+const AuditTableName = "audit_log"
+
+// AuditUpSQL creates the table that the audited writes are recorded in. The values are the columns of the row
+// before and after the write, and only hold the columns that changed when both are set.
+const AuditUpSQL = `CREATE TABLE audit_log
+(
+    id          BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    table_name  VARCHAR(64)     NOT NULL,
+    primary_key JSON            NOT NULL,
+    operation   VARCHAR(16)     NOT NULL,
+    old_values  JSON            NULL,
+    new_values  JSON            NULL,
+    actor       VARCHAR(255)    NOT NULL DEFAULT '',
+    created_at  DATETIME(6)     NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_audit_log_table_name (table_name, created_at)
+);
+`
+
+// AuditDownSQL drops the table created by AuditUpSQL.
+const AuditDownSQL = `DROP TABLE IF EXISTS audit_log;
+`
+
+// auditor decides which tables have their writes recorded in the audit log
+type auditor struct {
+	enabled bool
+}
+
+func newAuditor(cfg *config.Config) auditor {
+	return auditor{enabled: cfg != nil && cfg.Audit}
+}
+
+// auditEnabled returns true if the writes are audited
+func (a auditor) auditEnabled() bool {
+	return a.enabled
+}
+
+// audited returns true if the writes of the table are recorded in the audit log. Rows are recorded by their key, so
+// tables without one are not audited, and neither is the audit log itself.
+func (a auditor) audited(t *entities.Table) bool {
+	if !a.enabled || t.IsView || strings.EqualFold(t.Name, AuditTableName) {
+		return false
+	}
+
+	return len(identityColumns(t)) > 0
+}
+
+// auditSchema returns the schema of the audit log table
+func (a auditor) auditSchema() string {
+	return AuditUpSQL
+}
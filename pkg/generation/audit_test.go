@@ -0,0 +1,34 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestAudited(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int"}
+	pk := &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}}
+
+	tests := []struct {
+		name  string
+		cfg   *config.Config
+		table *entities.Table
+		want  bool
+	}{
+		{name: "audited", cfg: &config.Config{Audit: true}, table: &entities.Table{Name: "posts", Columns: []*entities.Column{id}, PrimaryKey: pk}, want: true},
+		{name: "not_enabled", cfg: new(config.Config), table: &entities.Table{Name: "posts", Columns: []*entities.Column{id}, PrimaryKey: pk}},
+		{name: "no_config", table: &entities.Table{Name: "posts", Columns: []*entities.Column{id}, PrimaryKey: pk}},
+		{name: "view", cfg: &config.Config{Audit: true}, table: &entities.Table{Name: "posts", IsView: true, Columns: []*entities.Column{id}, PrimaryKey: pk}},
+		{name: "no_key", cfg: &config.Config{Audit: true}, table: &entities.Table{Name: "posts", Columns: []*entities.Column{id}}},
+		{name: "audit_log", cfg: &config.Config{Audit: true}, table: &entities.Table{Name: "Audit_Log", Columns: []*entities.Column{id}, PrimaryKey: pk}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newAuditor(tt.cfg).audited(tt.table); got != tt.want {
+				t.Errorf("audited() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
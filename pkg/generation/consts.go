@@ -1,5 +1,34 @@
 package generation
 
+import "errors"
+
 const (
 	NullInt64 = "usql.NullInt64"
+
+	// uuidSize is the size of a BINARY column holding a UUID
+	uuidSize = 16
+
+	// defaultDecimalPrecision is the precision of a DECIMAL column declared without one
+	defaultDecimalPrecision = 10
+)
+
+var (
+	// ErrUnknownType is returned when a column type has no Go type mapping
+	ErrUnknownType = errors.New("unknown column type")
+
+	// ErrInvalidGoType is returned when a column is annotated with a Go type that can not be used
+	ErrInvalidGoType = errors.New("invalid Go type")
+
+	// ErrInvalidName is returned when a table or column is annotated with a name that is not an exported Go
+	// identifier
+	ErrInvalidName = errors.New("invalid Go name")
+
+	// ErrInvalidTag is returned when a column is annotated with a struct tag that can not be used
+	ErrInvalidTag = errors.New("invalid struct tag")
+
+	// ErrInvalidVersion is returned when a column is annotated as the version of its table but can not be one
+	ErrInvalidVersion = errors.New("invalid version column")
+
+	// ErrInvalidTimestamp is returned when a column is annotated as a created or updated timestamp but can not be one
+	ErrInvalidTimestamp = errors.New("invalid timestamp column")
 )
@@ -0,0 +1,208 @@
+package generation
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+var (
+	// majorVersionRegex matches the major version suffix of an import path element, e.g. "v2" or "yaml.v3"
+	majorVersionRegex = regexp.MustCompile(`(^|\.)v[0-9]+$`)
+
+	// identRegex matches the characters that are not valid in a Go identifier
+	identRegex = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// annotatedType returns the Go type and import spec of the type given by a `goschema:type` annotation. For example
+// "[]github.com/acme/pkg.Item" is the type "[]pkg.Item" imported from "github.com/acme/pkg". Types that do not
+// need an import, such as "map[string]any", are returned unchanged with an empty import spec.
+func annotatedType(value string) (string, string, error) {
+	// Keep any slice and pointer prefixes.
+	name := strings.TrimLeft(value, "[]*")
+	prefix := value[:len(value)-len(name)]
+	if strings.HasPrefix(name, "map[") || !strings.Contains(name, ".") {
+		return value, "", nil
+	}
+
+	i := strings.LastIndex(name, ".")
+	importPath, typeName := name[:i], name[i+1:]
+	if importPath == "" || !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		return "", "", fmt.Errorf("%w %q: expected an exported type such as github.com/acme/pkg.Type", ErrInvalidGoType, value)
+	}
+
+	pkg := packageName(importPath)
+	spec := strconv.Quote(importPath)
+	if pkg != path.Base(importPath) {
+		spec = pkg + " " + spec
+	}
+
+	return prefix + pkg + "." + typeName, spec, nil
+}
+
+// packageName guesses the package name of an import path, following the conventions for major version suffixes
+func packageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if majorVersionRegex.MatchString(name) {
+		if trimmed := majorVersionRegex.ReplaceAllString(name, ""); trimmed != "" {
+			// e.g. gopkg.in/yaml.v3
+			name = trimmed
+		} else if len(elems) > 1 {
+			// e.g. github.com/acme/pkg/v2
+			name = elems[len(elems)-2]
+		}
+	}
+
+	name = identRegex.ReplaceAllString(strings.TrimPrefix(name, "go-"), "_")
+	if !token.IsIdentifier(name) {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// columnImports returns the import specs needed by the annotated column types of the table
+func columnImports(t *entities.Table) []string {
+	seen := make(map[string]struct{})
+	ret := make([]string, 0)
+	for _, col := range t.Columns {
+		value, ok := col.Annotation(entities.AnnotationType)
+		if !ok {
+			continue
+		}
+
+		_, spec, err := annotatedType(value)
+		if err != nil || spec == "" {
+			// Invalid types are reported by typeMapper.validate.
+			continue
+		}
+		if _, ok := seen[spec]; ok {
+			continue
+		}
+		seen[spec] = struct{}{}
+		ret = append(ret, spec)
+	}
+
+	sort.Strings(ret)
+	return ret
+}
+
+// typeMapper maps columns to Go types. A `goschema:type` annotation takes precedence, followed by the type rules
+// of the config, the generated enum types and then the default mapping of getType.
+type typeMapper struct {
+	rules map[*entities.Column]config.TypeRule
+	enums map[*entities.Column]enumType
+}
+
+func newTypeMapper(tables []*entities.Table, cfg *config.Config, names *namer) *typeMapper {
+	m := &typeMapper{
+		rules: make(map[*entities.Column]config.TypeRule),
+		enums: enumTypes(tables, names),
+	}
+
+	owners := columnOwners(tables)
+	seen := make(map[*entities.Column]struct{})
+	for _, t := range tablesFirst(tables) {
+		for _, col := range t.Columns {
+			if _, ok := seen[col]; ok {
+				continue
+			}
+			seen[col] = struct{}{}
+			if _, ok := col.Annotation(entities.AnnotationType); ok {
+				continue
+			}
+			if rule, ok := viewTypeRule(cfg, owners, col); ok {
+				m.rules[col] = rule
+				delete(m.enums, col)
+				continue
+			}
+			if rule, ok := cfg.TypeRule(t.Name, col); ok {
+				m.rules[col] = rule
+				delete(m.enums, col)
+			}
+		}
+	}
+
+	return m
+}
+
+// viewTypeRule returns the type rule of the table column that a view column selects, matched by the name of the
+// table and column it is selected from and the nullability of the view column.
+func viewTypeRule(cfg *config.Config, owners map[*entities.Column]*entities.Table, col *entities.Column) (config.TypeRule, bool) {
+	if col.Source == nil {
+		return config.TypeRule{}, false
+	}
+	t, ok := owners[col.Source]
+	if !ok {
+		return config.TypeRule{}, false
+	}
+
+	src := *col
+	src.Name = col.Source.Name
+	return cfg.TypeRule(t.Name, &src)
+}
+
+// goType returns the Go type of the given column
+func (m *typeMapper) goType(col *entities.Column) (string, error) {
+	if rule, ok := m.rules[col]; ok {
+		return rule.GoType, nil
+	}
+	if e, ok := m.enums[col]; ok {
+		return nullable(col, e.Name), nil
+	}
+
+	return getType(col)
+}
+
+// imports returns the import specs needed by the column types of the table
+func (m *typeMapper) imports(t *entities.Table) []string {
+	ret := columnImports(t)
+	for _, col := range t.Columns {
+		rule, ok := m.rules[col]
+		if !ok || rule.Import == "" {
+			continue
+		}
+
+		spec := ruleImport(rule)
+		if !slices.Contains(ret, spec) {
+			ret = append(ret, spec)
+		}
+	}
+
+	sort.Strings(ret)
+	return ret
+}
+
+// validate checks that every column of the given tables has a Go type
+func (m *typeMapper) validate(tables []*entities.Table) error {
+	for _, t := range tables {
+		for _, col := range t.Columns {
+			if _, err := m.goType(col); err != nil {
+				return fmt.Errorf("table %q column %q: %w", t.Name, col.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleImport returns the import spec of a type rule. The package is imported with the name used by the rule's Go
+// type if that differs from the last element of the import path.
+func ruleImport(rule config.TypeRule) string {
+	spec := strconv.Quote(rule.Import)
+	name, _, ok := strings.Cut(strings.TrimLeft(rule.GoType, "[]*"), ".")
+	if ok && name != path.Base(rule.Import) {
+		spec = name + " " + spec
+	}
+
+	return spec
+}
@@ -0,0 +1,72 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnnotatedType(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		wantType   string
+		wantImport string
+		wantErr    error
+	}{
+		{
+			name:       "package_type",
+			in:         "github.com/acme/pkg.Settings",
+			wantType:   "pkg.Settings",
+			wantImport: `"github.com/acme/pkg"`,
+		},
+		{
+			name:       "slice_of_pointers",
+			in:         "[]*github.com/acme/pkg.Item",
+			wantType:   "[]*pkg.Item",
+			wantImport: `"github.com/acme/pkg"`,
+		},
+		{
+			name:       "major_version",
+			in:         "github.com/acme/pkg/v2.Settings",
+			wantType:   "pkg.Settings",
+			wantImport: `pkg "github.com/acme/pkg/v2"`,
+		},
+		{
+			name:       "gopkg_version",
+			in:         "gopkg.in/yaml.v3.Node",
+			wantType:   "yaml.Node",
+			wantImport: `yaml "gopkg.in/yaml.v3"`,
+		},
+		{
+			name:       "dashed_package",
+			in:         "github.com/acme/go-settings.Settings",
+			wantType:   "settings.Settings",
+			wantImport: `settings "github.com/acme/go-settings"`,
+		},
+		{
+			name:     "builtin",
+			in:       "map[string]any",
+			wantType: "map[string]any",
+		},
+		{
+			name:    "unexported",
+			in:      "github.com/acme/pkg.settings",
+			wantErr: ErrInvalidGoType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotImport, err := annotatedType(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("annotatedType() error = %v, want %v", err, tt.wantErr)
+			}
+			if gotType != tt.wantType {
+				t.Errorf("annotatedType() type = %q, want %q", gotType, tt.wantType)
+			}
+			if gotImport != tt.wantImport {
+				t.Errorf("annotatedType() import = %q, want %q", gotImport, tt.wantImport)
+			}
+		})
+	}
+}
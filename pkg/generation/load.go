@@ -1,29 +1,64 @@
 package generation
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/jacobbrewer1/goschema/pkg/entities"
+	"github.com/jacobbrewer1/goschema/pkg/logging"
+	"github.com/jacobbrewer1/goschema/pkg/migrations"
 	"github.com/pingcap/tidb/pkg/parser"
 	"github.com/pingcap/tidb/pkg/parser/ast"
 	_ "github.com/pingcap/tidb/pkg/parser/test_driver"
 )
 
-// LoadSQL loads all SQL files in the given paths and parses them
+// viewColumnAnnotation is the comment used to declare the type of a view column that cannot be inferred from
+// the tables the view selects from, e.g.
+//
+//	-- goschema:view_column order_totals.total DECIMAL(10,2) NOT NULL
+const viewColumnAnnotation = "goschema:view_column"
+
+// LoadSQL loads all SQL files in the given paths and applies them to a single schema, in the order the paths are
+// given and with the files of a directory in lexical order. Statements that refer to a table declared in a later
+// file are applied once all files have been read, and a table declared in more than one file takes the last
+// declaration.
 func LoadSQL(paths ...string) ([]*entities.Table, error) {
+	files, err := sqlFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+
 	p := parser.New()
-	tables := make([]*entities.Table, 0)
+	schema := entities.NewSchema()
+	stmts := make([]statement, 0)
+	for _, f := range files {
+		fileStmts, err := parseSQL(p, schema, f)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", f, err)
+		}
+		stmts = append(stmts, fileStmts...)
+	}
+
+	if err := applySchema(schema, stmts); err != nil {
+		return nil, err
+	}
 
+	return schema.Tables(), nil
+}
+
+// sqlFiles returns the SQL files matched by the given paths. Directories match the SQL files within them.
+func sqlFiles(paths ...string) ([]string, error) {
+	files := make([]string, 0)
 	for _, path := range paths {
 		matches, err := filepath.Glob(path)
 		if err != nil {
 			return nil, err
 		}
 		for _, m := range matches {
-			var matchedTables []*entities.Table
 			if fi, err := os.Stat(m); err != nil {
 				return nil, err
 			} else if fi.IsDir() {
@@ -31,30 +66,143 @@ func LoadSQL(paths ...string) ([]*entities.Table, error) {
 				if sErr != nil {
 					return nil, fmt.Errorf("error globbing %s: %w", m, sErr)
 				}
-				for _, sqlPath := range sqlMatches {
-					if matchedTables, err = parseSQL(p, sqlPath); err != nil {
-						return nil, fmt.Errorf("error parsing %s: %w", sqlPath, err)
-					}
-					tables = append(tables, matchedTables...)
-				}
+				files = append(files, sqlMatches...)
 			} else {
-				if matchedTables, err = parseSQL(p, m); err != nil {
-					return nil, fmt.Errorf("error parsing %s: %w", m, err)
-				}
-				tables = append(tables, matchedTables...)
+				files = append(files, m)
 			}
 		}
 	}
 
-	return tables, nil
+	return files, nil
 }
 
-func parseSQL(p *parser.Parser, path string) ([]*entities.Table, error) {
-	sql, err := os.ReadFile(path)
+// LoadMigrations builds the tables by replaying the up migrations in the given location in version order
+func LoadMigrations(location string) ([]*entities.Table, error) {
+	files, err := migrations.UpFiles(location)
 	if err != nil {
+		return nil, fmt.Errorf("error getting migrations from %s: %w", location, err)
+	}
+
+	p := parser.New()
+	schema := entities.NewSchema()
+	stmts := make([]statement, 0)
+	for _, f := range files {
+		fileStmts, err := parseSQL(p, schema, f)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", f, err)
+		}
+		stmts = append(stmts, fileStmts...)
+	}
+
+	if err := applyMigrations(schema, stmts); err != nil {
 		return nil, err
 	}
 
+	return schema.Tables(), nil
+}
+
+// statement is a parsed statement of a SQL file
+type statement struct {
+	stmt ast.StmtNode
+	path string
+	line int
+
+	// hints are the column attributes removed from the statement before parsing.
+	hints []columnHint
+}
+
+// applySchema applies the statements of schema files. Unlike migrations, schema files are not written to be
+// replayed in order, so statements that refer to a missing table are retried once all other statements have been
+// applied, and a table that is created again replaces the earlier declaration.
+func applySchema(schema *entities.Schema, stmts []statement) error {
+	deferred := make([]statement, 0)
+	for _, s := range stmts {
+		err := applyStatement(schema, s, false)
+		switch {
+		case errors.Is(err, entities.ErrTableNotFound):
+			deferred = append(deferred, s)
+		case errors.Is(err, entities.ErrTableExists):
+			if err := replaceTable(schema, s); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+	}
+
+	for _, s := range deferred {
+		if err := applyStatement(schema, s, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceTable replaces an existing table with the table created by the given statement
+func replaceTable(schema *entities.Schema, s statement) error {
+	ct, ok := s.stmt.(*ast.CreateTableStmt)
+	if !ok {
+		return applyStatement(schema, s, false)
+	}
+
+	slog.Warn("Table is declared more than once, using the last declaration",
+		slog.String(logging.KeyFile, s.path),
+		slog.Int(logging.KeyLine, s.line),
+		slog.String(logging.KeyTable, ct.Table.Name.String()),
+	)
+
+	if err := schema.Apply(&ast.DropTableStmt{Tables: []*ast.TableName{ct.Table}}); err != nil {
+		return fmt.Errorf("%s: line %d: %w", s.path, s.line, err)
+	}
+
+	return applyStatement(schema, s, false)
+}
+
+// applyMigrations replays the statements of migrations in order. Data migrations are expected in migrations, so
+// they are skipped without a warning.
+func applyMigrations(schema *entities.Schema, stmts []statement) error {
+	for _, s := range stmts {
+		if err := applyStatement(schema, s, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyStatement applies the statement to the schema. Statements that do not describe a table are skipped with a
+// warning.
+func applyStatement(schema *entities.Schema, s statement, skipDML bool) error {
+	err := schema.Apply(s.stmt)
+	switch {
+	case err == nil:
+		applyColumnHints(schema, s.stmt, s.path, s.hints)
+	case errors.Is(err, entities.ErrUnsupportedStatement):
+		if _, ok := s.stmt.(ast.DMLNode); ok && skipDML {
+			return nil
+		}
+
+		slog.Warn("Skipping unsupported statement",
+			slog.String(logging.KeyFile, s.path),
+			slog.Int(logging.KeyLine, s.line),
+			slog.String(logging.KeyType, fmt.Sprintf("%T", s.stmt)),
+		)
+	default:
+		return fmt.Errorf("error applying %s: line %d: %w", s.path, s.line, err)
+	}
+
+	return nil
+}
+
+// readSQL reads the given SQL file, removing any syntax that the parser does not support. Column attributes
+// that are removed are returned as hints.
+func readSQL(path string) (string, []columnHint, error) {
+	sql, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
 	// Loop through each line and remove any `with system versioning` clauses
 	lines := strings.Split(string(sql), "\n")
 	newSql := ""
@@ -66,28 +214,133 @@ func parseSQL(p *parser.Parser, path string) ([]*entities.Table, error) {
 		}
 		lines[i] = newSql
 	}
-	sql = []byte(strings.Join(lines, "\n"))
 
-	stmts, _, err := p.ParseSQL(string(sql))
+	processed, hints := preprocessSQL(strings.Join(lines, "\n"))
+	return processed, hints, nil
+}
+
+// parseSQL parses the given SQL file, returning its statements
+func parseSQL(p *parser.Parser, schema *entities.Schema, path string) ([]statement, error) {
+	sql, hints, err := readSQL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := annotateViewColumns(p, schema, sql); err != nil {
+		return nil, err
+	}
+
+	stmts, _, err := p.ParseSQL(sql)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing SQL: %w", err)
 	}
 
-	tables := make([]*entities.Table, 0, len(stmts))
-	for _, stmt := range stmts {
-		ct, ok := stmt.(*ast.CreateTableStmt)
+	lines := newLineFinder(sql)
+	starts := make([]int, len(stmts))
+	for i, stmt := range stmts {
+		starts[i] = lines.find(stmt.Text())
+	}
+
+	ret := make([]statement, len(stmts))
+	for i, stmt := range stmts {
+		ret[i] = statement{
+			stmt:  stmt,
+			path:  path,
+			line:  starts[i],
+			hints: statementHints(hints, starts, i),
+		}
+	}
+
+	return ret, nil
+}
+
+// annotateViewColumns adds the view column annotations found in the SQL comments to the schema
+func annotateViewColumns(p *parser.Parser, schema *entities.Schema, sql string) error {
+	for i, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		comment, ok := strings.CutPrefix(line, "--")
+		if !ok {
+			comment, ok = strings.CutPrefix(line, "#")
+		}
+		if !ok {
+			continue
+		}
+
+		annotation, ok := strings.CutPrefix(strings.TrimSpace(comment), viewColumnAnnotation)
 		if !ok {
-			// We only support create table statements
 			continue
 		}
 
-		t, err := entities.NewTable(ct)
+		target, typ, _ := strings.Cut(strings.TrimSpace(annotation), " ")
+		view, column, ok := strings.Cut(target, ".")
+		if !ok || view == "" || column == "" || strings.TrimSpace(typ) == "" {
+			return fmt.Errorf("line %d: invalid view column annotation, expected `%s <view>.<column> <type>`", i+1, viewColumnAnnotation)
+		}
+
+		// Parse the type as a single column table so that it is handled exactly like a table column.
+		stmt, err := p.ParseOneStmt(fmt.Sprintf("CREATE TABLE `%s` (`%s` %s)", view, column, typ), "", "")
 		if err != nil {
-			return nil, fmt.Errorf("error creating table from statement: %w", err)
+			return fmt.Errorf("line %d: error parsing view column annotation: %w", i+1, err)
+		}
+
+		ct, ok := stmt.(*ast.CreateTableStmt)
+		if !ok || len(ct.Cols) != 1 {
+			return fmt.Errorf("line %d: invalid view column type %q", i+1, typ)
+		}
+
+		if err := schema.AnnotateViewColumn(view, ct.Cols[0]); err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
 		}
+	}
+
+	return nil
+}
+
+// statementHints returns the hints that fall within the lines of the statement at the given index
+func statementHints(hints []columnHint, starts []int, idx int) []columnHint {
+	ret := make([]columnHint, 0)
+	if starts[idx] == 0 {
+		// The statement could not be located in the file.
+		return ret
+	}
+
+	for _, h := range hints {
+		if h.line < starts[idx] {
+			continue
+		}
+		if idx+1 < len(starts) && starts[idx+1] > 0 && h.line >= starts[idx+1] {
+			continue
+		}
+		ret = append(ret, h)
+	}
+
+	return ret
+}
+
+// lineFinder finds the line numbers of consecutive statements in a SQL file
+type lineFinder struct {
+	sql    string
+	offset int
+}
+
+func newLineFinder(sql string) *lineFinder {
+	return &lineFinder{sql: sql}
+}
+
+// find returns the line that the given statement text starts on, searching from the end of the
+// previously found statement. Zero is returned if the statement cannot be found.
+func (l *lineFinder) find(text string) int {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0
+	}
 
-		tables = append(tables, t)
+	idx := strings.Index(l.sql[l.offset:], text)
+	if idx == -1 {
+		return 0
 	}
 
-	return tables, nil
+	start := l.offset + idx
+	l.offset = start + len(text)
+	return strings.Count(l.sql[:start], "\n") + 1
 }
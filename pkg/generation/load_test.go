@@ -0,0 +1,314 @@
+package generation
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+)
+
+// captureLogs records the logs written during the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return buf
+}
+
+// The statements are built by hand so that loading is tested independently of the parser.
+
+func createStmt(table string, cols ...string) *ast.CreateTableStmt {
+	ct := &ast.CreateTableStmt{Table: &ast.TableName{Name: model.NewCIStr(table)}}
+	for _, col := range cols {
+		ct.Cols = append(ct.Cols, &ast.ColumnDef{
+			Name: &ast.ColumnName{Name: model.NewCIStr(col)},
+			Tp:   types.NewFieldType(mysql.TypeLong),
+		})
+	}
+	return ct
+}
+
+func addColumnStmt(table, col string) *ast.AlterTableStmt {
+	return &ast.AlterTableStmt{
+		Table: &ast.TableName{Name: model.NewCIStr(table)},
+		Specs: []*ast.AlterTableSpec{{
+			Tp: ast.AlterTableAddColumns,
+			NewColumns: []*ast.ColumnDef{{
+				Name: &ast.ColumnName{Name: model.NewCIStr(col)},
+				Tp:   types.NewFieldType(mysql.TypeLong),
+			}},
+		}},
+	}
+}
+
+func createViewStmt(view, table string) *ast.CreateViewStmt {
+	return &ast.CreateViewStmt{
+		ViewName: &ast.TableName{Name: model.NewCIStr(view)},
+		Select: &ast.SelectStmt{
+			Fields: &ast.FieldList{Fields: []*ast.SelectField{{WildCard: &ast.WildCardField{}}}},
+			From: &ast.TableRefsClause{TableRefs: &ast.Join{
+				Left: &ast.TableSource{Source: &ast.TableName{Name: model.NewCIStr(table)}},
+			}},
+		},
+	}
+}
+
+func tableColumns(tables []*entities.Table) []string {
+	ret := make([]string, len(tables))
+	for i, t := range tables {
+		cols := make([]string, len(t.Columns))
+		for j, col := range t.Columns {
+			cols[j] = col.Name
+		}
+		ret[i] = t.Name + "(" + strings.Join(cols, ", ") + ")"
+	}
+	return ret
+}
+
+func TestSQLFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.sql", "a.sql", "notes.txt", "c.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "views"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "views", "a.sql"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sqlFiles(filepath.Join(dir, "views"), dir, filepath.Join(dir, "c.sql"))
+	if err != nil {
+		t.Fatalf("sqlFiles() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "views", "a.sql"),
+		filepath.Join(dir, "a.sql"),
+		filepath.Join(dir, "b.sql"),
+		filepath.Join(dir, "c.sql"),
+		filepath.Join(dir, "c.sql"),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("sqlFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestReadSQL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schema.sql")
+	sql := "CREATE TABLE places (\n" +
+		"  id int,\n" +
+		"  `location`\n" +
+		"    POINT NOT NULL SRID 4326\n" +
+		") with system versioning;\n" +
+		"INSERT INTO places VALUES (1, point);\n"
+	if err := os.WriteFile(path, []byte(sql), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, hints, err := readSQL(path)
+	if err != nil {
+		t.Fatalf("readSQL() error = %v", err)
+	}
+
+	want := "CREATE TABLE places (\n" +
+		"  id int,\n" +
+		"  `location`\n" +
+		"    BLOB  NOT NULL          \n" +
+		") ;\n" +
+		"INSERT INTO places VALUES (1, point);\n"
+	if got != want {
+		t.Errorf("readSQL() = %q, want %q", got, want)
+	}
+	if len(hints) != 1 || hints[0].column != "location" || hints[0].line != 3 {
+		t.Errorf("readSQL() hints = %+v, want the location column on line 3", hints)
+	}
+}
+
+func TestApplySchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		stmts   []statement
+		want    []string
+		wantLog string
+		wantErr error
+	}{
+		{
+			name: "in order",
+			stmts: []statement{
+				{stmt: createStmt("users", "id"), path: "a.sql", line: 1},
+				{stmt: addColumnStmt("users", "age"), path: "b.sql", line: 1},
+			},
+			want: []string{"users(id, age)"},
+		},
+		{
+			name: "alter before create",
+			stmts: []statement{
+				{stmt: addColumnStmt("users", "age"), path: "a.sql", line: 1},
+				{stmt: createStmt("users", "id"), path: "b.sql", line: 1},
+				{stmt: createStmt("posts", "id"), path: "b.sql", line: 5},
+			},
+			want: []string{"users(id, age)", "posts(id)"},
+		},
+		{
+			name: "view before table",
+			stmts: []statement{
+				{stmt: createViewStmt("active_users", "users"), path: "a.sql", line: 1},
+				{stmt: createStmt("users", "id", "name"), path: "b.sql", line: 1},
+			},
+			want: []string{"users(id, name)", "active_users(id, name)"},
+		},
+		{
+			name: "missing table",
+			stmts: []statement{
+				{stmt: addColumnStmt("users", "age"), path: "a.sql", line: 3},
+				{stmt: createStmt("posts", "id"), path: "b.sql", line: 1},
+			},
+			wantErr: entities.ErrTableNotFound,
+		},
+		{
+			name: "declared twice",
+			stmts: []statement{
+				{stmt: createStmt("users", "id"), path: "a.sql", line: 1},
+				{stmt: createStmt("posts", "id"), path: "a.sql", line: 5},
+				{stmt: createStmt("users", "id", "name"), path: "b.sql", line: 2},
+			},
+			want:    []string{"posts(id)", "users(id, name)"},
+			wantLog: "Table is declared more than once",
+		},
+		{
+			name: "unsupported statement",
+			stmts: []statement{
+				{stmt: createStmt("users", "id"), path: "a.sql", line: 1},
+				{stmt: &ast.InsertStmt{}, path: "a.sql", line: 4},
+			},
+			want:    []string{"users(id)"},
+			wantLog: "Skipping unsupported statement",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+			schema := entities.NewSchema()
+
+			err := applySchema(schema, tt.stmts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("applySchema() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applySchema() error = %v", err)
+			}
+
+			if got := tableColumns(schema.Tables()); !slices.Equal(got, tt.want) {
+				t.Errorf("applySchema() tables = %v, want %v", got, tt.want)
+			}
+			if tt.wantLog != "" && !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("applySchema() logs = %q, want %q", logs.String(), tt.wantLog)
+			}
+			if tt.wantLog == "" && logs.Len() > 0 {
+				t.Errorf("applySchema() logs = %q, want none", logs.String())
+			}
+		})
+	}
+}
+
+func TestApplyMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		stmts   []statement
+		want    []string
+		wantLog string
+		wantErr error
+	}{
+		{
+			name: "replay",
+			stmts: []statement{
+				{stmt: createStmt("users", "id"), path: "1_init.up.sql", line: 1},
+				{stmt: createStmt("posts", "id"), path: "1_init.up.sql", line: 5},
+				{stmt: addColumnStmt("users", "age"), path: "2_age.up.sql", line: 1},
+				{stmt: &ast.DropTableStmt{Tables: []*ast.TableName{{Name: model.NewCIStr("posts")}}}, path: "3_posts.up.sql", line: 1},
+			},
+			want: []string{"users(id, age)"},
+		},
+		{
+			name: "data migration",
+			stmts: []statement{
+				{stmt: createStmt("users", "id"), path: "1_init.up.sql", line: 1},
+				{stmt: &ast.InsertStmt{}, path: "2_seed.up.sql", line: 1},
+			},
+			want: []string{"users(id)"},
+		},
+		{
+			name: "unsupported statement",
+			stmts: []statement{
+				{stmt: createStmt("users", "id"), path: "1_init.up.sql", line: 1},
+				{stmt: &ast.TruncateTableStmt{Table: &ast.TableName{Name: model.NewCIStr("users")}}, path: "2_reset.up.sql", line: 1},
+			},
+			want:    []string{"users(id)"},
+			wantLog: "Skipping unsupported statement",
+		},
+		{
+			name: "alter before create",
+			stmts: []statement{
+				{stmt: addColumnStmt("users", "age"), path: "1_age.up.sql", line: 1},
+				{stmt: createStmt("users", "id"), path: "2_init.up.sql", line: 1},
+			},
+			wantErr: entities.ErrTableNotFound,
+		},
+		{
+			name: "declared twice",
+			stmts: []statement{
+				{stmt: createStmt("users", "id"), path: "1_init.up.sql", line: 1},
+				{stmt: createStmt("users", "id"), path: "2_init.up.sql", line: 1},
+			},
+			wantErr: entities.ErrTableExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureLogs(t)
+			schema := entities.NewSchema()
+
+			err := applyMigrations(schema, tt.stmts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("applyMigrations() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyMigrations() error = %v", err)
+			}
+
+			if got := tableColumns(schema.Tables()); !slices.Equal(got, tt.want) {
+				t.Errorf("applyMigrations() tables = %v, want %v", got, tt.want)
+			}
+			if tt.wantLog != "" && !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("applyMigrations() logs = %q, want %q", logs.String(), tt.wantLog)
+			}
+			if tt.wantLog == "" && logs.Len() > 0 {
+				t.Errorf("applyMigrations() logs = %q, want none", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package generation
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/huandu/xstrings"
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// commonInitialisms are the initialisms that golint expects to be in upper case
+var commonInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "LHS",
+	"QPS", "RAM", "RHS", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "UID", "UUID", "URI",
+	"URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS",
+}
+
+// defaultNames names the Go identifiers of tables when there is no config
+var defaultNames = newNamer(nil, nil)
+
+// namer names the Go identifiers generated for tables and columns. A `goschema:name` or `goschema:plural`
+// annotation takes precedence, followed by the naming config and then the structified table or column name.
+type namer struct {
+	initialisms map[string]struct{}
+	cfg         *config.Config
+	tables      map[string]*entities.Table
+	owners      map[*entities.Column]*entities.Table
+}
+
+func newNamer(tables []*entities.Table, cfg *config.Config) *namer {
+	n := &namer{
+		initialisms: make(map[string]struct{}, len(commonInitialisms)),
+		cfg:         cfg,
+		tables:      make(map[string]*entities.Table, len(tables)),
+	}
+
+	for _, initialism := range commonInitialisms {
+		n.initialisms[initialism] = struct{}{}
+	}
+	if cfg != nil {
+		for _, initialism := range cfg.Naming.Initialisms {
+			n.initialisms[strings.ToUpper(initialism)] = struct{}{}
+		}
+	}
+
+	for _, t := range tables {
+		n.tables[strings.ToLower(t.Name)] = t
+	}
+
+	// A view column is named after the table column it selects, unless it is given another name.
+	n.owners = columnOwners(tables)
+	for _, t := range tables {
+		if !t.IsView {
+			continue
+		}
+		for _, col := range t.Columns {
+			if _, ok := n.owners[col]; ok {
+				continue
+			}
+			if col.Source == nil {
+				n.owners[col] = t
+				continue
+			}
+			if owner, ok := n.owners[col.Source]; ok && strings.EqualFold(col.Name, col.Source.Name) {
+				n.owners[col] = owner
+			} else {
+				n.owners[col] = t
+			}
+		}
+	}
+
+	return n
+}
+
+// structify converts a snake case name into an exported Go name, keeping initialisms in upper case
+func (n *namer) structify(s string) string {
+	words := strings.Split(xstrings.ToSnakeCase(s), "_")
+	sb := new(strings.Builder)
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		if _, ok := n.initialisms[strings.ToUpper(word)]; ok {
+			sb.WriteString(strings.ToUpper(word))
+			continue
+		}
+		sb.WriteString(xstrings.FirstRuneToUpper(word))
+	}
+
+	return sb.String()
+}
+
+// structName returns the name of the struct of the given table
+func (n *namer) structName(t *entities.Table) string {
+	if name, ok := t.Annotation(entities.AnnotationName); ok {
+		return name
+	}
+
+	return n.tableName(t.Name)
+}
+
+// tableName returns the name of the struct of the named table, which need not be one of the rendered tables
+func (n *namer) tableName(table string) string {
+	if t, ok := n.tables[strings.ToLower(table)]; ok {
+		if name, ok := t.Annotation(entities.AnnotationName); ok {
+			return name
+		}
+	}
+	if naming, ok := n.cfg.TableNaming(table); ok && naming.Name != "" {
+		return naming.Name
+	}
+
+	return n.structify(table)
+}
+
+// pluralName returns the plural of the struct name of the given table
+func (n *namer) pluralName(t *entities.Table) string {
+	if plural, ok := t.Annotation(entities.AnnotationPlural); ok {
+		return plural
+	}
+	if naming, ok := n.cfg.TableNaming(t.Name); ok && naming.Plural != "" {
+		return naming.Plural
+	}
+
+	return pluralize(n.structName(t))
+}
+
+// fieldName returns the name of the struct field of the given column
+func (n *namer) fieldName(col *entities.Column) string {
+	if name, ok := col.Annotation(entities.AnnotationName); ok {
+		return name
+	}
+
+	if t, ok := n.owners[col]; ok {
+		return n.columnName(t.Name, col.Name)
+	}
+
+	return n.structify(col.Name)
+}
+
+// columnName returns the name of the struct field of the named column of a table, which need not be one of the
+// rendered tables
+func (n *namer) columnName(table, column string) string {
+	if t, ok := n.tables[strings.ToLower(table)]; ok {
+		if col, ok := t.Column(column); ok {
+			if name, ok := col.Annotation(entities.AnnotationName); ok {
+				return name
+			}
+		}
+	}
+	if naming, ok := n.cfg.TableNaming(table); ok {
+		if name, ok := naming.ColumnName(column); ok {
+			return name
+		}
+	}
+
+	return n.structify(column)
+}
+
+// foreignStructName returns the name of the struct of the table referenced by a foreign key
+func (n *namer) foreignStructName(c entities.Constraint) string {
+	return n.tableName(c.ReferenceTable)
+}
+
+// foreignFieldName returns the name of the struct field of a column referenced by a foreign key
+func (n *namer) foreignFieldName(c entities.Constraint, column string) string {
+	return n.columnName(c.ReferenceTable, column)
+}
+
+// validate checks that the annotated names of the given tables are exported Go identifiers
+func (n *namer) validate(tables []*entities.Table) error {
+	for _, t := range tables {
+		for _, key := range []string{entities.AnnotationName, entities.AnnotationPlural} {
+			if name, ok := t.Annotation(key); ok && !isExportedIdent(name) {
+				return fmt.Errorf("table %q: %w %q", t.Name, ErrInvalidName, name)
+			}
+		}
+		for _, col := range t.Columns {
+			if name, ok := col.Annotation(entities.AnnotationName); ok && !isExportedIdent(name) {
+				return fmt.Errorf("table %q column %q: %w %q", t.Name, col.Name, ErrInvalidName, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isExportedIdent(name string) bool {
+	return token.IsIdentifier(name) && token.IsExported(name)
+}
+
+// unexport converts an exported Go name into an unexported one, lowering a leading initialism as a whole, e.g.
+// "ID" becomes "id" and "URLPath" becomes "urlPath"
+func unexport(s string) string {
+	r := []rune(s)
+	upper := 0
+	for upper < len(r) && unicode.IsUpper(r[upper]) {
+		upper++
+	}
+
+	switch {
+	case upper == 0:
+		return s
+	case upper == 1, upper == len(r):
+		// e.g. "User" or "ID"
+	case unicode.IsLetter(r[upper]):
+		// The last upper case letter starts the next word, e.g. the "P" of "URLPath".
+		upper--
+	}
+
+	for i := 0; i < upper; i++ {
+		r[i] = unicode.ToLower(r[i])
+	}
+
+	return string(r)
+}
+
+// pluralize returns the plural of an English noun. Names that already end in a single "s", such as those of
+// plural table names, are left as they are.
+func pluralize(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case lower == "":
+		return s
+	case strings.HasSuffix(lower, "ss"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	case strings.HasSuffix(lower, "s"):
+		return s
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	default:
+		return s + "s"
+	}
+}
@@ -0,0 +1,118 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestUnexport(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "UserID", want: "userID"},
+		{in: "ID", want: "id"},
+		{in: "URLPath", want: "urlPath"},
+		{in: "HTTPURL", want: "httpurl"},
+		{in: "Name", want: "name"},
+		{in: "name", want: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := unexport(tt.in); got != tt.want {
+				t.Errorf("unexport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "User", want: "Users"},
+		{in: "Users", want: "Users"},
+		{in: "Address", want: "Addresses"},
+		{in: "Box", want: "Boxes"},
+		{in: "Batch", want: "Batches"},
+		{in: "Category", want: "Categories"},
+		{in: "Key", want: "Keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := pluralize(tt.in); got != tt.want {
+				t.Errorf("pluralize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamer(t *testing.T) {
+	uid := &entities.Column{Name: "uid"}
+	sku := &entities.Column{Name: "sku"}
+	annotated := &entities.Column{Name: "ref", Annotations: map[string]string{entities.AnnotationName: "Reference"}}
+	people := &entities.Table{Name: "people", Columns: []*entities.Column{uid, sku, annotated}}
+	view := &entities.Table{Name: "people_view", IsView: true, Columns: []*entities.Column{uid}}
+	boxes := &entities.Table{
+		Name:        "box",
+		Annotations: map[string]string{entities.AnnotationName: "Crate", entities.AnnotationPlural: "Crates"},
+	}
+
+	cfg := &config.Config{
+		Naming: config.Naming{
+			Initialisms: []string{"sku"},
+			Tables: map[string]*config.TableNaming{
+				"people": {
+					Name:    "Person",
+					Plural:  "People",
+					Columns: map[string]string{"uid": "UserID", "ref": "Ignored"},
+				},
+				"box": {Name: "Ignored"},
+			},
+		},
+	}
+
+	tables := []*entities.Table{view, people, boxes}
+	n := newNamer(tables, cfg)
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "struct_name", got: n.structName(people), want: "Person"},
+		{name: "plural_name", got: n.pluralName(people), want: "People"},
+		{name: "annotated_struct_name", got: n.structName(boxes), want: "Crate"},
+		{name: "annotated_plural_name", got: n.pluralName(boxes), want: "Crates"},
+		{name: "default_plural_name", got: n.pluralName(view), want: "PeopleViews"},
+		{name: "field_name", got: n.fieldName(uid), want: "UserID"},
+		{name: "extra_initialism", got: n.fieldName(sku), want: "SKU"},
+		{name: "annotated_field_name", got: n.fieldName(annotated), want: "Reference"},
+		{name: "foreign_struct_name", got: n.foreignStructName(entities.Constraint{ReferenceTable: "people"}), want: "Person"},
+		{name: "foreign_field_name", got: n.foreignFieldName(entities.Constraint{ReferenceTable: "people"}, "uid"), want: "UserID"},
+		{name: "unknown_foreign_table", got: n.foreignStructName(entities.Constraint{ReferenceTable: "api_keys"}), want: "APIKeys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamerValidate(t *testing.T) {
+	col := &entities.Column{Name: "uid", Annotations: map[string]string{entities.AnnotationName: "userID"}}
+	tables := []*entities.Table{{Name: "users", Columns: []*entities.Column{col}}}
+
+	if err := newNamer(tables, nil).validate(tables); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("validate() error = %v, want %v", err, ErrInvalidName)
+	}
+}
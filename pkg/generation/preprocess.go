@@ -0,0 +1,331 @@
+package generation
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+	"github.com/jacobbrewer1/goschema/pkg/logging"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+// spatialTypes are the spatial column types, which the parser does not support
+var spatialTypes = []string{
+	"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon",
+	"geometrycollection", "geomcollection",
+}
+
+// definitionKeywords are the keywords that start a definition that is not a column, e.g. an index
+var definitionKeywords = []string{
+	"index", "key", "unique", "primary", "fulltext", "spatial", "constraint", "foreign", "check",
+	"create", "alter", "drop", "rename", "partition",
+}
+
+// spatialPlaceholder is the type that spatial columns are parsed as. It is replaced by the spatial type once the
+// statement has been parsed.
+const spatialPlaceholder = "BLOB"
+
+// columnHint records a column attribute that the parser does not support. The attribute is removed from the SQL
+// before parsing and applied to the column once the statement declaring it has been applied.
+type columnHint struct {
+	line   int
+	column string
+	apply  func(col *entities.Column)
+}
+
+// sqlToken is a word, quoted identifier or punctuation character of a line of SQL
+type sqlToken struct {
+	text   string
+	start  int
+	end    int
+	quoted bool
+}
+
+// is returns true if the token is the given unquoted keyword
+func (t sqlToken) is(keyword string) bool {
+	return !t.quoted && strings.EqualFold(t.text, keyword)
+}
+
+// isWord returns true if the token is an identifier or keyword rather than punctuation
+func (t sqlToken) isWord() bool {
+	return t.quoted || (t.text != "" && isWordByte(t.text[0]))
+}
+
+// preprocessSQL removes the column types and attributes that the parser does not support from the CREATE TABLE and
+// ALTER TABLE statements of the SQL, returning the hints needed to restore them. Other statements, such as inserts,
+// are left untouched, and the length of every line is kept so that positions are unchanged.
+func preprocessSQL(sql string) (string, []columnHint) {
+	hints := make([]columnHint, 0)
+	for _, stmt := range splitStatements(tokenizeSQL(sql)) {
+		if !isCreateTable(stmt) && !isAlterTable(stmt) {
+			continue
+		}
+
+		for _, def := range tableDefinitions(stmt) {
+			// The column name and type come before any attributes.
+			if len(def) < 2 || !def[1].isWord() || slices.ContainsFunc(definitionKeywords, def[0].is) {
+				continue
+			}
+			column := def[0].text
+			line := 1 + strings.Count(sql[:def[0].start], "\n")
+
+			if slices.ContainsFunc(spatialTypes, def[1].is) {
+				typ := strings.ToLower(def[1].text)
+				if typ == "geomcollection" {
+					typ = "geometrycollection"
+				}
+
+				sql = sql[:def[1].start] + padRight(spatialPlaceholder, def[1].end-def[1].start) + sql[def[1].end:]
+				for i := 2; i+1 < len(def); i++ {
+					if def[i].is("SRID") {
+						sql = blank(sql, def[i])
+						sql = blank(sql, def[i+1])
+					}
+				}
+
+				hints = append(hints, columnHint{
+					line:   line,
+					column: column,
+					apply: func(col *entities.Column) {
+						col.Type = typ
+						col.TypeSize = 0
+						col.Charset = ""
+						col.Collation = ""
+					},
+				})
+			}
+
+			depth := 0
+			for _, tok := range def[2:] {
+				switch {
+				case tok.is("("):
+					depth++
+				case tok.is(")"):
+					depth--
+				case depth == 0 && tok.is("INVISIBLE"):
+					sql = blank(sql, tok)
+					hints = append(hints, columnHint{
+						line:   line,
+						column: column,
+						apply: func(col *entities.Column) {
+							col.Invisible = true
+						},
+					})
+				}
+			}
+		}
+	}
+
+	return sql, hints
+}
+
+// blank replaces the token in the line with spaces
+func blank(line string, tok sqlToken) string {
+	return line[:tok.start] + strings.Repeat(" ", tok.end-tok.start) + line[tok.end:]
+}
+
+// padRight pads the string with spaces to the given length
+func padRight(s string, length int) string {
+	if len(s) >= length {
+		return s
+	}
+	return s + strings.Repeat(" ", length-len(s))
+}
+
+// tokenizeSQL splits the SQL into words, quoted identifiers and punctuation. String literals and comments are
+// skipped, so their contents are never mistaken for keywords.
+func tokenizeSQL(sql string) []sqlToken {
+	tokens := make([]sqlToken, 0)
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '#' || strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '\'':
+			i = skipQuoted(sql, i)
+		case c == '`' || c == '"':
+			end := skipQuoted(sql, i)
+			tokens = append(tokens, sqlToken{text: strings.Trim(sql[i:end], string(c)), start: i, end: end, quoted: true})
+			i = end
+		case isWordByte(c):
+			end := i
+			for end < len(sql) && isWordByte(sql[end]) {
+				end++
+			}
+			tokens = append(tokens, sqlToken{text: sql[i:end], start: i, end: end})
+			i = end
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		default:
+			tokens = append(tokens, sqlToken{text: sql[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+
+	return tokens
+}
+
+// skipQuoted returns the offset after the quoted string or identifier starting at the given offset
+func skipQuoted(line string, start int) int {
+	quote := line[start]
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if quote == '\'' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+
+	return len(line)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// splitStatements splits the tokens into the statements they hold
+func splitStatements(tokens []sqlToken) [][]sqlToken {
+	stmts := make([][]sqlToken, 0)
+	for len(tokens) > 0 {
+		end := slices.IndexFunc(tokens, func(tok sqlToken) bool { return tok.is(";") })
+		if end < 0 {
+			end = len(tokens)
+		}
+		if end > 0 {
+			stmts = append(stmts, tokens[:end])
+		}
+		tokens = tokens[min(end+1, len(tokens)):]
+	}
+
+	return stmts
+}
+
+// tableDefinitions splits the tokens of a CREATE TABLE or ALTER TABLE statement into the table element definitions
+// it holds, e.g. the columns and indexes of the table, with any statement or alter specification prefix removed.
+func tableDefinitions(tokens []sqlToken) [][]sqlToken {
+	defs := make([][]sqlToken, 0)
+	var def []sqlToken
+	flush := func() {
+		if len(def) > 0 {
+			defs = append(defs, definitionTokens(def))
+		}
+		def = nil
+	}
+
+	depth := 0
+	for _, tok := range tokens {
+		switch {
+		case tok.is("("):
+			if depth == 0 && isCreateTable(def) {
+				// The opening parenthesis of the table definition.
+				def = nil
+				continue
+			}
+			depth++
+		case tok.is(")"):
+			if depth == 0 {
+				// The closing parenthesis of the table definition.
+				flush()
+				continue
+			}
+			depth--
+		case depth == 0 && tok.is(","):
+			flush()
+			continue
+		}
+
+		def = append(def, tok)
+	}
+	flush()
+
+	return defs
+}
+
+// isCreateTable returns true if the tokens are the start of a CREATE TABLE statement
+func isCreateTable(tokens []sqlToken) bool {
+	if len(tokens) < 3 || !tokens[0].is("CREATE") {
+		return false
+	}
+	return tokens[1].is("TABLE") || (tokens[1].is("TEMPORARY") && tokens[2].is("TABLE"))
+}
+
+// isAlterTable returns true if the tokens are the start of an ALTER TABLE statement
+func isAlterTable(tokens []sqlToken) bool {
+	return len(tokens) > 2 && tokens[0].is("ALTER") && tokens[1].is("TABLE")
+}
+
+// definitionTokens returns the tokens of a table element definition, removing any statement or alter
+// specification prefix from the given tokens.
+func definitionTokens(tokens []sqlToken) []sqlToken {
+	// The first specification of an alter statement, e.g. `ALTER TABLE t ADD COLUMN c INT INVISIBLE`
+	if len(tokens) > 3 && isAlterTable(tokens) {
+		tokens = tokens[3:]
+		for len(tokens) > 1 && tokens[0].is(".") {
+			// Schema qualified table name
+			tokens = tokens[2:]
+		}
+	}
+
+	if len(tokens) > 0 {
+		switch {
+		case tokens[0].is("ADD"), tokens[0].is("MODIFY"), tokens[0].is("CHANGE"):
+			change := tokens[0].is("CHANGE")
+			tokens = tokens[1:]
+			if len(tokens) > 0 && tokens[0].is("COLUMN") {
+				tokens = tokens[1:]
+			}
+			if change && len(tokens) > 0 {
+				// Skip the old column name
+				tokens = tokens[1:]
+			}
+		}
+	}
+
+	return tokens
+}
+
+// applyColumnHints applies the hints to the table created or altered by the given statement
+func applyColumnHints(schema *entities.Schema, stmt ast.StmtNode, path string, hints []columnHint) {
+	if len(hints) == 0 {
+		return
+	}
+
+	var name string
+	switch stmt := stmt.(type) {
+	case *ast.CreateTableStmt:
+		name = stmt.Table.Name.String()
+	case *ast.AlterTableStmt:
+		name = stmt.Table.Name.String()
+	}
+
+	t, ok := schema.Table(name)
+	for _, h := range hints {
+		var col *entities.Column
+		if ok {
+			col, _ = t.Column(h.column)
+		}
+		if col == nil {
+			slog.Warn("Unable to find the column for a column attribute, ignoring",
+				slog.String(logging.KeyFile, path),
+				slog.Int(logging.KeyLine, h.line),
+			)
+			continue
+		}
+
+		h.apply(col)
+	}
+}
@@ -0,0 +1,155 @@
+package generation
+
+import "testing"
+
+func TestPreprocessSQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		want       string
+		wantColumn string
+		wantLine   int
+	}{
+		{
+			name:       "column_definition",
+			in:         "CREATE TABLE t (\n  `secret` varchar(10) INVISIBLE,\n  id int\n);",
+			want:       "CREATE TABLE t (\n  `secret` varchar(10)          ,\n  id int\n);",
+			wantColumn: "secret",
+			wantLine:   2,
+		},
+		{
+			name:       "alter_add_column",
+			in:         "ALTER TABLE users ADD COLUMN token char(36) invisible;",
+			want:       "ALTER TABLE users ADD COLUMN token char(36)          ;",
+			wantColumn: "token",
+			wantLine:   1,
+		},
+		{
+			name:       "alter_change_column",
+			in:         "ALTER TABLE users\n  ADD COLUMN a int,\n  CHANGE old_token token char(36) INVISIBLE;",
+			want:       "ALTER TABLE users\n  ADD COLUMN a int,\n  CHANGE old_token token char(36)          ;",
+			wantColumn: "token",
+			wantLine:   3,
+		},
+		{
+			name:       "spatial_type",
+			in:         "CREATE TABLE t (\n  `location` POINT NOT NULL SRID 4326\n);",
+			want:       "CREATE TABLE t (\n  `location` BLOB  NOT NULL          \n);",
+			wantColumn: "location",
+			wantLine:   2,
+		},
+		{
+			name: "spatial_column_name",
+			in:   "CREATE TABLE t (\n  `point` int NOT NULL\n);",
+			want: "CREATE TABLE t (\n  `point` int NOT NULL\n);",
+		},
+		{
+			name: "invisible_index",
+			in:   "CREATE TABLE t (\n  secret int,\n  KEY idx_secret (secret) INVISIBLE\n);",
+			want: "CREATE TABLE t (\n  secret int,\n  KEY idx_secret (secret) INVISIBLE\n);",
+		},
+		{
+			name:       "inline_primary_key",
+			in:         "CREATE TABLE t (id INT PRIMARY KEY INVISIBLE);",
+			want:       "CREATE TABLE t (id INT PRIMARY KEY          );",
+			wantColumn: "id",
+			wantLine:   1,
+		},
+		{
+			name:       "inline_unique_key",
+			in:         "CREATE TABLE t (`code` char(3) NOT NULL UNIQUE KEY INVISIBLE);",
+			want:       "CREATE TABLE t (`code` char(3) NOT NULL UNIQUE KEY          );",
+			wantColumn: "code",
+			wantLine:   1,
+		},
+		{
+			name:       "keyword_column_name",
+			in:         "CREATE TABLE t (`key` varchar(10) INVISIBLE);",
+			want:       "CREATE TABLE t (`key` varchar(10)          );",
+			wantColumn: "key",
+			wantLine:   1,
+		},
+		{
+			name:       "single_line_create",
+			in:         "CREATE TABLE t (id INT PRIMARY KEY, secret INT INVISIBLE, KEY idx_secret (secret) INVISIBLE);",
+			want:       "CREATE TABLE t (id INT PRIMARY KEY, secret INT          , KEY idx_secret (secret) INVISIBLE);",
+			wantColumn: "secret",
+			wantLine:   1,
+		},
+		{
+			name:       "multiple_columns",
+			in:         "CREATE TABLE t (\n  a INT INVISIBLE, b POINT SRID 4326 INVISIBLE\n);",
+			want:       "CREATE TABLE t (\n  a INT          , b BLOB                     \n);",
+			wantColumn: "a",
+			wantLine:   2,
+		},
+		{
+			name:       "schema_qualified_alter",
+			in:         "ALTER TABLE app.users ADD COLUMN token char(36) INVISIBLE;",
+			want:       "ALTER TABLE app.users ADD COLUMN token char(36)          ;",
+			wantColumn: "token",
+			wantLine:   1,
+		},
+		{
+			name: "alter_index",
+			in:   "ALTER TABLE users ALTER INDEX idx_secret INVISIBLE;",
+			want: "ALTER TABLE users ALTER INDEX idx_secret INVISIBLE;",
+		},
+		{
+			name: "create_index",
+			in:   "CREATE INDEX idx_secret ON users (secret) INVISIBLE;",
+			want: "CREATE INDEX idx_secret ON users (secret) INVISIBLE;",
+		},
+		{
+			name: "enum_values",
+			in:   "CREATE TABLE t (\n  status ENUM('visible', 'invisible', 'it\\'s point') NOT NULL\n);",
+			want: "CREATE TABLE t (\n  status ENUM('visible', 'invisible', 'it\\'s point') NOT NULL\n);",
+		},
+		{
+			name:       "multi_line_definition",
+			in:         "CREATE TABLE t (\n  `location`\n    POINT\n    NOT NULL\n    SRID 4326,\n  secret\n    int\n    INVISIBLE\n);",
+			want:       "CREATE TABLE t (\n  `location`\n    BLOB \n    NOT NULL\n             ,\n  secret\n    int\n             \n);",
+			wantColumn: "location",
+			wantLine:   2,
+		},
+		{
+			name: "comment",
+			in:   "CREATE TABLE t (\n  name varchar(10) COMMENT 'invisible' -- invisible\n  /* point invisible */\n);",
+			want: "CREATE TABLE t (\n  name varchar(10) COMMENT 'invisible' -- invisible\n  /* point invisible */\n);",
+		},
+		{
+			name: "commented_statement",
+			in:   "-- ALTER TABLE users ADD COLUMN token char(36) INVISIBLE;\n/* CREATE TABLE t (a POINT); */",
+			want: "-- ALTER TABLE users ADD COLUMN token char(36) INVISIBLE;\n/* CREATE TABLE t (a POINT); */",
+		},
+		{
+			name: "insert",
+			in:   "INSERT INTO shapes (name, kind) VALUES\n  ('a', 1), (point, invisible);",
+			want: "INSERT INTO shapes (name, kind) VALUES\n  ('a', 1), (point, invisible);",
+		},
+		{
+			name:       "statement_after_insert",
+			in:         "INSERT INTO t VALUES (1);\nALTER TABLE t ADD COLUMN secret int INVISIBLE;",
+			want:       "INSERT INTO t VALUES (1);\nALTER TABLE t ADD COLUMN secret int          ;",
+			wantColumn: "secret",
+			wantLine:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hints := preprocessSQL(tt.in)
+			if got != tt.want {
+				t.Errorf("preprocessSQL() = %q, want %q", got, tt.want)
+			}
+
+			gotColumn, gotLine := "", 0
+			if len(hints) > 0 {
+				gotColumn, gotLine = hints[0].column, hints[0].line
+			}
+			if gotColumn != tt.wantColumn || gotLine != tt.wantLine {
+				t.Errorf("preprocessSQL() column = %q on line %d, want %q on line %d", gotColumn, gotLine, tt.wantColumn, tt.wantLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package generation
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// defaultRelations is used by the template helpers when no tables are given
+var defaultRelations = newRelations(nil)
+
+// relation is a foreign key from a child table to a parent table. The columns are in the order of the unique key of
+// the parent that the foreign key references, which is the order of the parameters of the parent finder.
+type relation struct {
+	Constraint entities.Constraint
+
+	// Child is the table of the foreign key and Columns are its columns.
+	Child   *entities.Table
+	Columns []*entities.Column
+
+	// Parent is the referenced table and ParentColumns are the referenced columns. Both are nil when the referenced
+	// table is not rendered, in which case only ReferenceColumns are known.
+	Parent           *entities.Table
+	ParentColumns    []*entities.Column
+	ReferenceColumns []string
+
+	// HasFinder is true if a finder of a single parent row is generated for the referenced columns.
+	HasFinder bool
+
+	// Ambiguous is true if the child has more than one foreign key to the parent.
+	Ambiguous bool
+
+	// Batchable is true if the rows of both tables can be loaded in batches, keyed by the value of the foreign key.
+	Batchable bool
+}
+
+// SelfReferencing returns true if the foreign key references its own table
+func (r *relation) SelfReferencing() bool {
+	return r.Parent == r.Child
+}
+
+// relations holds the foreign keys between the rendered tables
+type relations struct {
+	parents  map[*entities.Table][]*relation
+	children map[*entities.Table][]*relation
+}
+
+func newRelations(tables []*entities.Table) *relations {
+	r := &relations{
+		parents:  make(map[*entities.Table][]*relation),
+		children: make(map[*entities.Table][]*relation),
+	}
+
+	byName := make(map[string]*entities.Table, len(tables))
+	for _, t := range tables {
+		if !t.IsView {
+			byName[strings.ToLower(t.Name)] = t
+		}
+	}
+
+	for _, t := range tables {
+		if t.IsView {
+			continue
+		}
+
+		for _, c := range t.Constraints {
+			rel, ok := newRelation(t, c, byName[strings.ToLower(c.ReferenceTable)])
+			if !ok {
+				continue
+			}
+
+			r.parents[t] = append(r.parents[t], rel)
+			if rel.Parent != nil {
+				r.children[rel.Parent] = append(r.children[rel.Parent], rel)
+			}
+		}
+	}
+
+	for _, rels := range r.parents {
+		for _, rel := range rels {
+			if rel.Parent == nil {
+				continue
+			}
+			n := 0
+			for _, other := range rels {
+				if other.Parent == rel.Parent {
+					n++
+				}
+			}
+			rel.Ambiguous = n > 1
+		}
+	}
+
+	return r
+}
+
+// newRelation returns the relation of a foreign key of the child, which is false if its columns are not known. The
+// parent is nil if the referenced table is not rendered.
+func newRelation(child *entities.Table, c entities.Constraint, parent *entities.Table) (*relation, bool) {
+	if len(c.Columns) == 0 {
+		// Constraints that are not parsed only have the references, which are ordered by the local column.
+		for _, name := range slices.Sorted(maps.Keys(c.References)) {
+			c.Columns = append(c.Columns, name)
+			c.ReferenceColumns = append(c.ReferenceColumns, c.References[name])
+		}
+	}
+	if len(c.Columns) == 0 || len(c.Columns) != len(c.ReferenceColumns) {
+		return nil, false
+	}
+
+	rel := &relation{
+		Constraint:       c,
+		Child:            child,
+		Columns:          make([]*entities.Column, 0, len(c.Columns)),
+		ReferenceColumns: slices.Clone(c.ReferenceColumns),
+	}
+	for _, name := range c.Columns {
+		col, ok := findColumn(child, name)
+		if !ok {
+			return nil, false
+		}
+		rel.Columns = append(rel.Columns, col)
+	}
+
+	if parent == nil {
+		// The finder of the parent is expected to take the columns in the order of the foreign key.
+		rel.HasFinder = true
+		return rel, true
+	}
+
+	rel.Parent = parent
+	rel.ParentColumns = make([]*entities.Column, 0, len(c.ReferenceColumns))
+	for _, name := range c.ReferenceColumns {
+		col, ok := findColumn(parent, name)
+		if !ok {
+			return nil, false
+		}
+		rel.ParentColumns = append(rel.ParentColumns, col)
+	}
+
+	if key, ok := parentFinderKey(parent, rel.ParentColumns); ok {
+		// Order the columns of both tables by the key, to match the parameters of the finder.
+		cols := make([]*entities.Column, 0, len(key.Columns))
+		for _, col := range key.Columns {
+			cols = append(cols, rel.Columns[slices.Index(rel.ParentColumns, col)])
+		}
+		rel.Columns = cols
+		rel.ParentColumns = slices.Clone(key.Columns)
+		rel.ReferenceColumns = make([]string, 0, len(key.Columns))
+		for _, col := range key.Columns {
+			rel.ReferenceColumns = append(rel.ReferenceColumns, col.Name)
+		}
+		rel.HasFinder = true
+	}
+
+	rel.Batchable = len(rel.Columns) == 1 &&
+		!rel.ParentColumns[0].Nullable &&
+		batchKeyKind(rel.ParentColumns[0]) != "" &&
+		batchKeyKind(rel.ParentColumns[0]) == batchKeyKind(rel.Columns[0])
+
+	return rel, true
+}
+
+// batchKeyKind returns the kind of the values of the column when they can be used as map keys, or an empty string.
+// Only integer, string and UUID columns are used, as the values of other types are either not comparable or not
+// equal when the values are.
+func batchKeyKind(col *entities.Column) string {
+	if _, ok := col.Annotation(entities.AnnotationType); ok {
+		return ""
+	}
+
+	switch strings.ToLower(col.Type) {
+	case "bigint", "int", "mediumint", "smallint":
+		return "int"
+	case "tinyint":
+		// TINYINT(1) is a bool
+		if col.TypeSize == 1 {
+			return ""
+		}
+		return "int"
+	case "char", "varchar":
+		return "string"
+	case "binary":
+		if col.TypeSize == uuidSize {
+			return "uuid"
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+// parentFinderKey returns the unique key of the table that has exactly the given columns and that a finder of a
+// single row is generated for
+func parentFinderKey(t *entities.Table, cols []*entities.Column) (entities.Key, bool) {
+	for _, key := range uniqueColumnKeys(t) {
+		if !isUniqueKey(key) || len(key.Columns) == len(t.Columns) || len(key.Columns) != len(cols) {
+			continue
+		}
+
+		match := true
+		for _, col := range key.Columns {
+			if !slices.Contains(cols, col) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return key, true
+		}
+	}
+
+	return entities.Key{}, false
+}
+
+// findColumn returns the named column of the table
+func findColumn(t *entities.Table, name string) (*entities.Column, bool) {
+	for _, col := range t.Columns {
+		if strings.EqualFold(col.Name, name) {
+			return col, true
+		}
+	}
+
+	return nil, false
+}
+
+// belongsTo returns the foreign keys of the table
+func (r *relations) belongsTo(t *entities.Table) []*relation {
+	return r.parents[t]
+}
+
+// hasMany returns the foreign keys of other tables that reference the table
+func (r *relations) hasMany(t *entities.Table) []*relation {
+	return r.children[t]
+}
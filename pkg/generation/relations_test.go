@@ -0,0 +1,156 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestRelations(t *testing.T) {
+	uid := &entities.Column{Name: "id", Type: "int"}
+	uname := &entities.Column{Name: "name", Type: "varchar"}
+	users := &entities.Table{
+		Name:       "users",
+		Columns:    []*entities.Column{uid, uname},
+		PrimaryKey: &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{uid}},
+	}
+
+	ref := &entities.Column{Name: "ref", Type: "char"}
+	yr := &entities.Column{Name: "yr", Type: "year"}
+	pid := &entities.Column{Name: "id", Type: "int"}
+	posts := &entities.Table{
+		Name:       "posts",
+		Columns:    []*entities.Column{pid, ref, yr},
+		PrimaryKey: &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{pid}},
+		Keys:       []entities.Key{{Name: "uniq_ref_yr", Type: "unique", Columns: []*entities.Column{ref, yr}}},
+	}
+
+	author := &entities.Column{Name: "author_id", Type: "int"}
+	editor := &entities.Column{Name: "editor_id", Type: "int", Nullable: true}
+	postYr := &entities.Column{Name: "post_yr", Type: "year"}
+	postRef := &entities.Column{Name: "post_ref", Type: "char"}
+	org := &entities.Column{Name: "org_id", Type: "int"}
+	comments := &entities.Table{
+		Name:    "comments",
+		Columns: []*entities.Column{author, editor, postYr, postRef, org},
+		Constraints: []entities.Constraint{
+			{Name: "fk_author", ReferenceTable: "Users", References: map[string]string{"author_id": "id"}},
+			{Name: "fk_editor", ReferenceTable: "users", Columns: []string{"editor_id"}, ReferenceColumns: []string{"id"}},
+			{Name: "fk_post", ReferenceTable: "posts", Columns: []string{"post_yr", "post_ref"}, ReferenceColumns: []string{"yr", "ref"}},
+			{Name: "fk_org", ReferenceTable: "orgs", Columns: []string{"org_id"}, ReferenceColumns: []string{"id"}},
+			{Name: "fk_name", ReferenceTable: "users", Columns: []string{"author_id"}, ReferenceColumns: []string{"name"}},
+			{Name: "fk_unknown", ReferenceTable: "users", Columns: []string{"missing"}, ReferenceColumns: []string{"id"}},
+		},
+	}
+
+	r := newRelations([]*entities.Table{users, posts, comments})
+
+	type want struct {
+		name      string
+		parent    *entities.Table
+		columns   []*entities.Column
+		refs      []string
+		hasFinder bool
+		ambiguous bool
+		batchable bool
+	}
+	wants := []want{
+		{name: "fk_author", parent: users, columns: []*entities.Column{author}, refs: []string{"id"}, hasFinder: true, ambiguous: true, batchable: true},
+		{name: "fk_editor", parent: users, columns: []*entities.Column{editor}, refs: []string{"id"}, hasFinder: true, ambiguous: true, batchable: true},
+		{name: "fk_post", parent: posts, columns: []*entities.Column{postRef, postYr}, refs: []string{"ref", "yr"}, hasFinder: true},
+		{name: "fk_org", columns: []*entities.Column{org}, refs: []string{"id"}, hasFinder: true},
+		{name: "fk_name", parent: users, columns: []*entities.Column{author}, refs: []string{"name"}, ambiguous: true},
+	}
+
+	got := r.belongsTo(comments)
+	if len(got) != len(wants) {
+		t.Fatalf("belongsTo() returned %d relations, want %d", len(got), len(wants))
+	}
+	for i, w := range wants {
+		rel := got[i]
+		if rel.Constraint.Name != w.name {
+			t.Errorf("belongsTo()[%d] = %s, want %s", i, rel.Constraint.Name, w.name)
+			continue
+		}
+		if rel.Parent != w.parent {
+			t.Errorf("%s: parent = %v, want %v", w.name, rel.Parent, w.parent)
+		}
+		if len(rel.Columns) != len(w.columns) {
+			t.Errorf("%s: columns = %v, want %v", w.name, rel.Columns, w.columns)
+		} else {
+			for j := range w.columns {
+				if rel.Columns[j] != w.columns[j] || rel.ReferenceColumns[j] != w.refs[j] {
+					t.Errorf("%s: column %d = %s -> %s, want %s -> %s", w.name, j,
+						rel.Columns[j].Name, rel.ReferenceColumns[j], w.columns[j].Name, w.refs[j])
+				}
+			}
+		}
+		if rel.HasFinder != w.hasFinder {
+			t.Errorf("%s: HasFinder = %v, want %v", w.name, rel.HasFinder, w.hasFinder)
+		}
+		if rel.Ambiguous != w.ambiguous {
+			t.Errorf("%s: Ambiguous = %v, want %v", w.name, rel.Ambiguous, w.ambiguous)
+		}
+		if rel.Batchable != w.batchable {
+			t.Errorf("%s: Batchable = %v, want %v", w.name, rel.Batchable, w.batchable)
+		}
+	}
+
+	if got := r.hasMany(users); len(got) != 3 {
+		t.Errorf("hasMany(users) returned %d relations, want 3", len(got))
+	}
+	if got := r.hasMany(posts); len(got) != 1 || got[0].Constraint.Name != "fk_post" {
+		t.Errorf("hasMany(posts) = %v, want fk_post", got)
+	}
+	if got := r.hasMany(comments); len(got) != 0 {
+		t.Errorf("hasMany(comments) = %v, want none", got)
+	}
+}
+
+func TestRelationsSelfReferencing(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "bigint"}
+	parent := &entities.Column{Name: "parent_id", Type: "bigint", Nullable: true}
+	categories := &entities.Table{
+		Name:        "categories",
+		Columns:     []*entities.Column{id, parent},
+		PrimaryKey:  &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}},
+		Constraints: []entities.Constraint{{Name: "fk_parent", ReferenceTable: "categories", References: map[string]string{"parent_id": "id"}}},
+	}
+
+	r := newRelations([]*entities.Table{categories})
+	got := r.belongsTo(categories)
+	if len(got) != 1 {
+		t.Fatalf("belongsTo() returned %d relations, want 1", len(got))
+	}
+	if !got[0].SelfReferencing() || got[0].Ambiguous || !got[0].Batchable {
+		t.Errorf("relation = %+v, want a batchable self referencing relation", got[0])
+	}
+	if children := r.hasMany(categories); len(children) != 1 || children[0] != got[0] {
+		t.Errorf("hasMany() = %v, want the same relation", children)
+	}
+}
+
+func TestBatchKeyKind(t *testing.T) {
+	tests := []struct {
+		name string
+		col  *entities.Column
+		want string
+	}{
+		{name: "bigint", col: &entities.Column{Type: "bigint", Unsigned: true}, want: "int"},
+		{name: "tinyint", col: &entities.Column{Type: "tinyint", TypeSize: 4}, want: "int"},
+		{name: "bool", col: &entities.Column{Type: "tinyint", TypeSize: 1}},
+		{name: "varchar", col: &entities.Column{Type: "VARCHAR", TypeSize: 36}, want: "string"},
+		{name: "uuid", col: &entities.Column{Type: "binary", TypeSize: 16}, want: "uuid"},
+		{name: "binary", col: &entities.Column{Type: "binary", TypeSize: 8}},
+		{name: "datetime", col: &entities.Column{Type: "datetime"}},
+		{name: "decimal", col: &entities.Column{Type: "decimal"}},
+		{name: "annotated", col: &entities.Column{Type: "varchar", Annotations: map[string]string{entities.AnnotationType: "example.com/pkg.ID"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := batchKeyKind(tt.col); got != tt.want {
+				t.Errorf("batchKeyKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package generation
+
+import (
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// softDeleter finds the columns that mark the rows of a table as soft deleted
+type softDeleter struct {
+	column string
+	goType func(col *entities.Column) (string, error)
+}
+
+func newSoftDeleter(cfg *config.Config, goType func(col *entities.Column) (string, error)) softDeleter {
+	return softDeleter{column: cfg.SoftDeleteColumn(), goType: goType}
+}
+
+// softDeleteColumn returns the soft delete column of the table, or nil if its rows are deleted. Soft deletes need a
+// key to update the row by, and a column of the usql.NullTime type.
+func (s softDeleter) softDeleteColumn(t *entities.Table) *entities.Column {
+	col, ok := t.SoftDeleteColumn(s.column)
+	if !ok || len(identityColumns(t)) == 0 {
+		return nil
+	}
+
+	if goType, err := s.goType(col); err != nil || goType != "usql.NullTime" {
+		return nil
+	}
+
+	return col
+}
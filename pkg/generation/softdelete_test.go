@@ -0,0 +1,78 @@
+package generation
+
+import (
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestSoftDeleteColumn(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int"}
+	pk := &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}}
+
+	tests := []struct {
+		name  string
+		cfg   *config.Config
+		table *entities.Table
+		want  string
+	}{
+		{
+			name:  "default",
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "deleted_at", Type: "datetime", Nullable: true}}, PrimaryKey: pk},
+			want:  "deleted_at",
+		},
+		{
+			name:  "timestamp",
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "Deleted_At", Type: "timestamp", Nullable: true}}, PrimaryKey: pk},
+			want:  "Deleted_At",
+		},
+		{
+			name:  "configured",
+			cfg:   &config.Config{SoftDelete: "archived_at"},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "deleted_at", Type: "datetime", Nullable: true}, {Name: "archived_at", Type: "datetime", Nullable: true}}, PrimaryKey: pk},
+			want:  "archived_at",
+		},
+		{
+			name:  "off",
+			cfg:   &config.Config{SoftDelete: config.NoSoftDelete},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "deleted_at", Type: "datetime", Nullable: true}}, PrimaryKey: pk},
+		},
+		{
+			name:  "not_null",
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "deleted_at", Type: "datetime"}}, PrimaryKey: pk},
+		},
+		{
+			name:  "not_a_time",
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "deleted_at", Type: "tinyint", TypeSize: 1, Nullable: true}}, PrimaryKey: pk},
+		},
+		{
+			name:  "no_key",
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "deleted_at", Type: "datetime", Nullable: true}}},
+		},
+		{
+			name:  "view",
+			table: &entities.Table{IsView: true, Columns: []*entities.Column{id, {Name: "deleted_at", Type: "datetime", Nullable: true}}, PrimaryKey: pk},
+		},
+		{
+			name:  "overridden_type",
+			cfg:   &config.Config{Types: []config.TypeRule{{Column: "deleted_at", GoType: "*time.Time"}}},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "deleted_at", Type: "datetime", Nullable: true}}, PrimaryKey: pk},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables := []*entities.Table{tt.table}
+			types := newTypeMapper(tables, tt.cfg, newNamer(tables, tt.cfg))
+			got := newSoftDeleter(tt.cfg, types.goType).softDeleteColumn(tt.table)
+
+			name := ""
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("softDeleteColumn() = %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
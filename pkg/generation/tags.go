@@ -0,0 +1,191 @@
+package generation
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/huandu/xstrings"
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// tagger builds the struct tags of the generated fields. The db tag is always generated, followed by the tags of
+// the config and then the tags given by `goschema:tag_<key>` annotations, which replace configured tags of the same
+// key.
+type tagger struct {
+	tags    config.Tags
+	goType  func(col *entities.Column) (string, error)
+	managed map[*entities.Column]struct{}
+}
+
+func newTagger(cfg *config.Config, goType func(col *entities.Column) (string, error), managed map[*entities.Column]struct{}) *tagger {
+	t := &tagger{goType: goType, managed: managed}
+	if cfg != nil {
+		t.tags = cfg.Tags
+	}
+
+	return t
+}
+
+// structTag returns the struct tag of the given column
+func (t *tagger) structTag(col *entities.Column) string {
+	annotated := annotatedTags(col)
+
+	tags := make([]string, 0)
+	add := func(key, value string) {
+		if v, ok := annotated[key]; ok {
+			value = v
+			delete(annotated, key)
+		}
+		if value != "" {
+			tags = append(tags, key+":"+strconv.Quote(value))
+		}
+	}
+
+	db := col.Name
+	if col.InPrimaryKey {
+		db += ",pk"
+	}
+	if col.AutoIncrementing {
+		db += ",autoinc"
+	}
+	tags = append(tags, "db:"+strconv.Quote(db))
+	if col.Generated {
+		// Generated columns cannot be written to
+		tags = append(tags, `patcher:"-"`)
+	}
+
+	for _, key := range t.tags.NameKeys() {
+		add(key, nameTag(t.tags.Names[key], col))
+	}
+	if t.tags.Validate {
+		add("validate", t.validateTag(col))
+	}
+
+	keys := make([]string, 0, len(annotated))
+	for key := range annotated {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add(key, annotated[key])
+	}
+
+	return "`" + strings.Join(tags, " ") + "`"
+}
+
+// nameTag returns the value of a tag holding the name of the column
+func nameTag(tag *config.NameTag, col *entities.Column) string {
+	name := xstrings.ToSnakeCase(col.Name)
+	if tag.Case == config.CaseCamel {
+		name = xstrings.FirstRuneToLower(xstrings.ToCamelCase(name))
+	}
+	if tag.OmitEmpty && col.Nullable {
+		name += ",omitempty"
+	}
+
+	return name
+}
+
+// validateTag returns the validation rules that follow from the column. Rules that check the value of the field are
+// only given for string fields, as the validator can not check the usql types.
+func (t *tagger) validateTag(col *entities.Column) string {
+	rules := make([]string, 0)
+
+	goType, err := t.goType(col)
+	if err != nil {
+		// Invalid types are reported by typeMapper.validate.
+		return ""
+	}
+
+	// Columns that the database or the generated writes fill in are not required, and a required bool could never be
+	// false.
+	if !col.Nullable && !col.HasDefault && !col.AutoIncrementing && !col.Generated && !t.isManaged(col) && goType != "bool" {
+		rules = append(rules, "required")
+	}
+
+	switch {
+	case col.Type == entities.TypeEnum && !col.Nullable && token.IsIdentifier(goType):
+		// The generated enum types, or a string
+		values := make([]string, 0, len(col.Elements))
+		for _, e := range col.Elements {
+			if strings.ContainsAny(e, " '") {
+				e = "'" + strings.ReplaceAll(e, "'", "") + "'"
+			}
+			values = append(values, e)
+		}
+		rules = append(rules, "oneof="+strings.Join(values, " "))
+	case goType == "string" && col.TypeSize > 0 && isCharType(col.Type):
+		rules = append(rules, fmt.Sprintf("max=%d", col.TypeSize))
+	}
+
+	return strings.Join(rules, ",")
+}
+
+// isManaged returns true if the column, or the table column that a view column is selected from, is set by the
+// generated writes
+func (t *tagger) isManaged(col *entities.Column) bool {
+	if _, ok := t.managed[col]; ok {
+		return true
+	}
+	if col.Source != nil {
+		_, ok := t.managed[col.Source]
+		return ok
+	}
+
+	return false
+}
+
+// managedColumns returns the timestamp and version columns of the tables, which the generated writes set
+func managedColumns(tables []*entities.Table, versions versioner, timestamps timestamper) map[*entities.Column]struct{} {
+	ret := make(map[*entities.Column]struct{})
+	for _, t := range tables {
+		for _, col := range []*entities.Column{timestamps.createdAtColumn(t), timestamps.updatedAtColumn(t), versions.versionColumn(t)} {
+			if col != nil {
+				ret[col] = struct{}{}
+			}
+		}
+	}
+
+	return ret
+}
+
+// isCharType returns true if the MySQL type holds a string with a maximum length in characters
+func isCharType(dbType string) bool {
+	switch strings.ToLower(dbType) {
+	case "char", "varchar":
+		return true
+	default:
+		return false
+	}
+}
+
+// annotatedTags returns the struct tags given by the `goschema:tag_<key>` annotations of the column
+func annotatedTags(col *entities.Column) map[string]string {
+	ret := make(map[string]string)
+	for key, value := range col.Annotations {
+		if tag, ok := strings.CutPrefix(key, entities.AnnotationTagPrefix); ok {
+			ret[tag] = value
+		}
+	}
+
+	return ret
+}
+
+// validate checks that the struct tags given by the annotations of the given tables can be used
+func (t *tagger) validate(tables []*entities.Table) error {
+	for _, tbl := range tables {
+		for _, col := range tbl.Columns {
+			for key := range annotatedTags(col) {
+				if !config.IsValidTagKey(key) || config.IsReservedTag(key) {
+					return fmt.Errorf("table %q column %q: %w %q", tbl.Name, col.Name, ErrInvalidTag, key)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestStructTag(t *testing.T) {
+	tags := config.Tags{
+		Names: map[string]*config.NameTag{
+			"json": {Case: config.CaseCamel, OmitEmpty: true},
+			"yaml": {},
+		},
+		Validate: true,
+	}
+
+	tests := []struct {
+		name string
+		tags config.Tags
+		col  *entities.Column
+		want string
+	}{
+		{
+			name: "default",
+			col:  &entities.Column{Name: "id", Type: "int", InPrimaryKey: true, AutoIncrementing: true},
+			want: "`db:\"id,pk,autoinc\"`",
+		},
+		{
+			name: "generated",
+			col:  &entities.Column{Name: "full_name", Type: "varchar", Generated: true},
+			want: "`db:\"full_name\" patcher:\"-\"`",
+		},
+		{
+			name: "required_varchar",
+			tags: tags,
+			col:  &entities.Column{Name: "user_name", Type: "varchar", TypeSize: 255},
+			want: "`db:\"user_name\" json:\"userName\" yaml:\"user_name\" validate:\"required,max=255\"`",
+		},
+		{
+			name: "nullable",
+			tags: tags,
+			col:  &entities.Column{Name: "bio", Type: "varchar", TypeSize: 255, Nullable: true},
+			want: "`db:\"bio\" json:\"bio,omitempty\" yaml:\"bio\"`",
+		},
+		{
+			name: "enum_with_default",
+			tags: tags,
+			col:  &entities.Column{Name: "status", Type: entities.TypeEnum, Elements: []string{"active", "on hold"}, HasDefault: true},
+			want: "`db:\"status\" json:\"status\" yaml:\"status\" validate:\"oneof=active 'on hold'\"`",
+		},
+		{
+			name: "annotated",
+			tags: tags,
+			col: &entities.Column{Name: "password", Type: "varchar", TypeSize: 60, HasDefault: true, Annotations: map[string]string{
+				"tag_json":         "-",
+				"tag_mapstructure": "pass",
+			}},
+			want: "`db:\"password\" json:\"-\" yaml:\"password\" validate:\"max=60\" mapstructure:\"pass\"`",
+		},
+		{
+			name: "created_at",
+			tags: tags,
+			col:  &entities.Column{Name: "created_at", Type: "datetime"},
+			want: "`db:\"created_at\" json:\"createdAt\" yaml:\"created_at\"`",
+		},
+		{
+			name: "updated_at",
+			tags: tags,
+			col:  &entities.Column{Name: "modified", Type: "timestamp", Annotations: map[string]string{entities.AnnotationUpdatedAt: ""}},
+			want: "`db:\"modified\" json:\"modified\" yaml:\"modified\"`",
+		},
+		{
+			name: "version",
+			tags: tags,
+			col:  &entities.Column{Name: "revision", Type: "int", Unsigned: true},
+			want: "`db:\"revision\" json:\"revision\" yaml:\"revision\"`",
+		},
+		{
+			name: "unmanaged_datetime",
+			tags: tags,
+			col:  &entities.Column{Name: "published_at", Type: "datetime"},
+			want: "`db:\"published_at\" json:\"publishedAt\" yaml:\"published_at\" validate:\"required\"`",
+		},
+	}
+
+	id := &entities.Column{Name: "id", Type: "int", InPrimaryKey: true}
+	pk := &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Tags: tt.tags, Version: "revision"}
+			tables := []*entities.Table{{Name: "users", Columns: []*entities.Column{id, tt.col}, PrimaryKey: pk}}
+			types := newTypeMapper(tables, cfg, newNamer(tables, cfg))
+			tagger := newTagger(cfg, types.goType, managedColumns(tables, newVersioner(cfg), newTimestamper(types.goType)))
+			if got := tagger.structTag(tt.col); got != tt.want {
+				t.Errorf("structTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaggerValidate(t *testing.T) {
+	col := &entities.Column{Name: "id", Annotations: map[string]string{"tag_db": "other"}}
+	tables := []*entities.Table{{Name: "users", Columns: []*entities.Column{col}}}
+
+	if err := newTagger(nil, getType, nil).validate(tables); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("validate() error = %v, want %v", err, ErrInvalidTag)
+	}
+}
@@ -2,11 +2,11 @@ package generation
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"text/template"
 
-	"github.com/huandu/xstrings"
 	"github.com/jacobbrewer1/goschema/pkg/entities"
 )
 
@@ -17,15 +17,44 @@ var Helpers = template.FuncMap{
 	"primary_autoinc_column": primaryAutoIncColumn,
 	"autoinc_column":         autoIncColumn,
 	"non_autoinc_columns":    nonAutoIncColumns,
-	"lcfirst":                xstrings.FirstRuneToLower,
+	"lcfirst":                unexport,
 	"identity_columns":       identityColumns,
 	"non_identity_columns":   nonIdentityColumns,
+	"writable_columns":       writableColumns,
+	"insert_columns":         insertColumns,
+	"update_columns":         updateColumns,
 	"structify":              structify,
+	"struct_name":            defaultNames.structName,
+	"plural_name":            defaultNames.pluralName,
+	"field_name":             defaultNames.fieldName,
+	"foreign_struct_name":    defaultNames.foreignStructName,
+	"foreign_field_name":     defaultNames.foreignFieldName,
 	"enum_columns":           enumColumns,
+	"set_columns":            setColumns,
+	"decimal_columns":        decimalColumns,
+	"decimal_precision":      decimalPrecision,
+	"decimal_scale":          decimalScale,
 	"unique_column_keys":     uniqueColumnKeys,
+	"index_finders":          indexFinders,
+	"belongs_to":             defaultRelations.belongsTo,
+	"soft_delete_column":     newSoftDeleter(nil, getType).softDeleteColumn,
+	"version_column":         newVersioner(nil).versionColumn,
+	"created_at_column":      newTimestamper(getType).createdAtColumn,
+	"audited":                auditor{}.audited,
+	"audit_enabled":          auditor{}.auditEnabled,
+	"audit_schema":           auditor{}.auditSchema,
+	"updated_at_column":      newTimestamper(getType).updatedAtColumn,
+	"exclude_column":         excludeColumn,
+	"has_many":               defaultRelations.hasMany,
+	"keyset_indexes":         keysetIndexes,
 	"sorted_columns":         sortedColumns,
 	"get_type":               getType,
+	"column_imports":         columnImports,
 	"get_tags":               getTags,
+	"legacy_signatures":      signatures{}.legacySignatures,
+	"ctx_param":              signatures{}.ctxParam,
+	"ctx_arg":                signatures{}.ctxArg,
+	"db_method":              signatures{}.dbMethod,
 }
 
 // hasPrimaryKey returns true if the table has a primary key
@@ -122,15 +151,49 @@ func nonIdentityColumns(t *entities.Table) []*entities.Column {
 	return ret
 }
 
+// writableColumns returns the columns that can be written to, which excludes generated columns
+func writableColumns(t *entities.Table) []*entities.Column {
+	return writable(t.Columns)
+}
+
+// insertColumns returns the columns to set when inserting a row, which excludes auto-incrementing and
+// generated columns
+func insertColumns(t *entities.Table) []*entities.Column {
+	return writable(nonAutoIncColumns(t))
+}
+
+// updateColumns returns the columns to set when updating a row, which excludes identity and generated columns
+func updateColumns(t *entities.Table) []*entities.Column {
+	return writable(nonIdentityColumns(t))
+}
+
+// excludeColumn returns the columns without the given column, which may be nil
+func excludeColumn(cols []*entities.Column, col *entities.Column) []*entities.Column {
+	ret := make([]*entities.Column, 0, len(cols))
+	for _, c := range cols {
+		if c != col {
+			ret = append(ret, c)
+		}
+	}
+
+	return ret
+}
+
+func writable(cols []*entities.Column) []*entities.Column {
+	ret := make([]*entities.Column, 0, len(cols))
+	for _, col := range cols {
+		if !col.Generated {
+			ret = append(ret, col)
+		}
+	}
+
+	return ret
+}
+
 // structify attempts to convert a string into a good struct field name
 // by following golint conventions
 func structify(s string) string {
-	s = xstrings.ToCamelCase(s)
-
-	// Capitalize the first letter
-	s = xstrings.FirstRuneToUpper(s)
-
-	return s
+	return defaultNames.structify(s)
 }
 
 // enumColumns returns the columns which are enum types
@@ -145,6 +208,202 @@ func enumColumns(t *entities.Table) []*entities.Column {
 	return ret
 }
 
+// enumType is the named Go type generated for an enum column
+type enumType struct {
+	Name   string
+	Table  *entities.Table
+	Column *entities.Column
+}
+
+// enumTypes returns the named Go types of the enum columns of the given tables, keyed by column. A type is named
+// after the table and column that declare it; view columns share the type of the table column they select.
+func enumTypes(tables []*entities.Table, names *namer) map[*entities.Column]enumType {
+	types := make(map[*entities.Column]enumType)
+	for _, t := range tablesFirst(tables) {
+		for _, col := range enumColumns(t) {
+			if _, ok := types[col]; ok {
+				continue
+			}
+			if _, ok := col.Annotation(entities.AnnotationType); ok {
+				continue
+			}
+			if col.Source != nil {
+				if e, ok := types[col.Source]; ok {
+					types[col] = e
+					continue
+				}
+			}
+			types[col] = enumType{
+				Name:   names.structName(t) + names.fieldName(col),
+				Table:  t,
+				Column: col,
+			}
+		}
+	}
+
+	return types
+}
+
+// columnOwners returns the tables of the given tables that declare each column, excluding views
+func columnOwners(tables []*entities.Table) map[*entities.Column]*entities.Table {
+	owners := make(map[*entities.Column]*entities.Table)
+	for _, t := range tables {
+		if t.IsView {
+			continue
+		}
+		for _, col := range t.Columns {
+			owners[col] = t
+		}
+	}
+
+	return owners
+}
+
+// tablesFirst returns the tables followed by the views
+func tablesFirst(tables []*entities.Table) []*entities.Table {
+	ret := make([]*entities.Table, 0, len(tables))
+	for _, t := range tables {
+		if !t.IsView {
+			ret = append(ret, t)
+		}
+	}
+	for _, t := range tables {
+		if t.IsView {
+			ret = append(ret, t)
+		}
+	}
+
+	return ret
+}
+
+// signatures writes the signatures of the generated database methods. The methods take a context.Context as their
+// first parameter and use the context aware methods of the DB, unless the legacy signatures are kept.
+type signatures struct {
+	legacy bool
+}
+
+// legacySignatures returns true if the methods are generated without a context.Context
+func (s signatures) legacySignatures() bool {
+	return s.legacy
+}
+
+// ctxParam returns the context parameter to put before the other parameters of a method
+func (s signatures) ctxParam() string {
+	if s.legacy {
+		return ""
+	}
+
+	return "ctx context.Context, "
+}
+
+// ctxArg returns the context argument to put before the other arguments of a call
+func (s signatures) ctxArg() string {
+	if s.legacy {
+		return ""
+	}
+
+	return "ctx, "
+}
+
+// dbMethod returns the name of the DB method to call, e.g. ExecContext for Exec
+func (s signatures) dbMethod(name string) string {
+	if s.legacy {
+		return name
+	}
+
+	return name + "Context"
+}
+
+// tableHelpers returns the template helpers that depend on the full set of tables and the config being rendered
+func tableHelpers(m *typeMapper, names *namer, tags *tagger, rels *relations, soft softDeleter, versions versioner, timestamps timestamper, audits auditor, sig signatures) template.FuncMap {
+	return template.FuncMap{
+		"audited":             audits.audited,
+		"audit_enabled":       audits.auditEnabled,
+		"audit_schema":        audits.auditSchema,
+		"created_at_column":   timestamps.createdAtColumn,
+		"updated_at_column":   timestamps.updatedAtColumn,
+		"version_column":      versions.versionColumn,
+		"soft_delete_column":  soft.softDeleteColumn,
+		"belongs_to":          rels.belongsTo,
+		"has_many":            rels.hasMany,
+		"legacy_signatures":   sig.legacySignatures,
+		"ctx_param":           sig.ctxParam,
+		"ctx_arg":             sig.ctxArg,
+		"db_method":           sig.dbMethod,
+		"structify":           names.structify,
+		"struct_name":         names.structName,
+		"plural_name":         names.pluralName,
+		"field_name":          names.fieldName,
+		"foreign_struct_name": names.foreignStructName,
+		"foreign_field_name":  names.foreignFieldName,
+		"get_type":            m.goType,
+		"get_tags":            tags.structTag,
+		"column_imports":      m.imports,
+		"decimal_columns": func(t *entities.Table) []*entities.Column {
+			// Only columns of the usql decimal types can be checked.
+			ret := make([]*entities.Column, 0)
+			for _, col := range decimalColumns(t) {
+				if goType, _ := m.goType(col); goType == "usql.Decimal" || goType == "usql.NullDecimal" {
+					ret = append(ret, col)
+				}
+			}
+			return ret
+		},
+		"enum_types": func(t *entities.Table) []enumType {
+			ret := make([]enumType, 0)
+			for _, col := range t.Columns {
+				if e, ok := m.enums[col]; ok && e.Table == t {
+					ret = append(ret, e)
+				}
+			}
+			return ret
+		},
+	}
+}
+
+// setColumns returns the columns which are set types
+func setColumns(t *entities.Table) []*entities.Column {
+	ret := make([]*entities.Column, 0)
+	for _, col := range t.Columns {
+		if col.Type == entities.TypeSet {
+			ret = append(ret, col)
+		}
+	}
+
+	return ret
+}
+
+// decimalColumns returns the writable columns which are decimal types
+func decimalColumns(t *entities.Table) []*entities.Column {
+	ret := make([]*entities.Column, 0)
+	for _, col := range writableColumns(t) {
+		if strings.EqualFold(col.Type, "decimal") {
+			ret = append(ret, col)
+		}
+	}
+
+	return ret
+}
+
+// decimalPrecision returns the maximum number of digits of a decimal column. MySQL defaults to 10 when the
+// precision is not given.
+func decimalPrecision(col *entities.Column) int {
+	if col.TypeSize <= 0 {
+		return defaultDecimalPrecision
+	}
+
+	return col.TypeSize
+}
+
+// decimalScale returns the number of digits after the decimal point of a decimal column
+func decimalScale(col *entities.Column) int {
+	if col.TypePrecision < 0 {
+		return 0
+	}
+
+	return col.TypePrecision
+}
+
 // uniqueColumnKeys returns a list of keys, none of which have the same set of columns as each other
 // This is required because you can have mulitple indexs including the exact same columns in the same order,
 // but of different types (unique, non-unique, etc). Includes the primary key, if any.
@@ -167,6 +426,141 @@ func uniqueColumnKeys(t *entities.Table) []entities.Key {
 	return keys
 }
 
+// indexFinder is a finder of the rows matching the leading columns of an index
+type indexFinder struct {
+	Index   entities.Key
+	Columns []*entities.Column
+}
+
+// Prefix returns true if the finder matches a left-prefix of the columns of the index
+func (f indexFinder) Prefix() bool {
+	return len(f.Columns) < len(f.Index.Columns)
+}
+
+// indexFinders returns the finders of the non-unique indexes of the table and of every left-prefix of its composite
+// indexes, which can match more than one row. Sets of columns that are unique, or that already have a finder, are
+// skipped.
+func indexFinders(t *entities.Table) []indexFinder {
+	keys := make([]entities.Key, 0, len(t.Keys)+1)
+	if t.PrimaryKey != nil {
+		keys = append(keys, *t.PrimaryKey)
+	}
+	keys = append(keys, t.Keys...)
+
+	seen := make(map[string]struct{})
+	for _, key := range keys {
+		if isUniqueKey(key) {
+			seen[fmt.Sprint(key.Columns)] = struct{}{}
+		}
+	}
+
+	ret := make([]indexFinder, 0)
+	for _, key := range keys {
+		if key.Type == "fulltext" {
+			// Full text indexes are searched with MATCH, not compared
+			continue
+		}
+
+		for i := range key.Columns {
+			cols := key.Columns[:i+1]
+			k := fmt.Sprint(cols)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			ret = append(ret, indexFinder{Index: key, Columns: cols})
+		}
+	}
+
+	return ret
+}
+
+// isUniqueKey returns true if the key is the primary key or a unique key
+func isUniqueKey(key entities.Key) bool {
+	return key.Type == "primary" || strings.HasPrefix(key.Type, "unique")
+}
+
+// keysetIndex is an index that the rows of a table can be paginated by. The rows are ordered by the columns of the
+// index followed by the primary key columns that are not in the index, so that the order is total.
+type keysetIndex struct {
+	Index   entities.Key
+	Columns []*entities.Column
+}
+
+// Primary returns true if the rows are paginated by the primary key
+func (k keysetIndex) Primary() bool {
+	return k.Index.Type == "primary"
+}
+
+// keysetIndexes returns the indexes that the rows of the table can be paginated by, starting with the primary key.
+// Pagination needs a primary key, and every column that the rows are ordered by must be NOT NULL and of a type that
+// is compared in the same order by MySQL and the cursor. Indexes ordering the rows the same as one before are skipped.
+func keysetIndexes(t *entities.Table) []keysetIndex {
+	if t.PrimaryKey == nil || !allKeysetColumns(t.PrimaryKey.Columns) {
+		return nil
+	}
+
+	keys := append([]entities.Key{*t.PrimaryKey}, t.Keys...)
+
+	seen := make(map[string]struct{})
+	ret := make([]keysetIndex, 0)
+	for _, key := range keys {
+		if key.Type == "fulltext" || !allKeysetColumns(key.Columns) {
+			continue
+		}
+
+		cols := slices.Clone(key.Columns)
+		if !isUniqueKey(key) {
+			for _, col := range t.PrimaryKey.Columns {
+				if !slices.Contains(cols, col) {
+					cols = append(cols, col)
+				}
+			}
+		}
+
+		k := fmt.Sprint(cols)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		ret = append(ret, keysetIndex{Index: key, Columns: cols})
+	}
+
+	return ret
+}
+
+// allKeysetColumns returns true if the rows can be paginated by all the given columns
+func allKeysetColumns(cols []*entities.Column) bool {
+	for _, col := range cols {
+		if !isKeysetColumn(col) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isKeysetColumn returns true if the rows can be paginated by the column. Floating point, text, enum and other
+// columns are not used, as their values are either not exact or not ordered the same in the database and in the cursor.
+func isKeysetColumn(col *entities.Column) bool {
+	if col.Nullable {
+		return false
+	}
+	if _, ok := col.Annotation(entities.AnnotationType); ok {
+		return false
+	}
+
+	switch strings.ToLower(col.Type) {
+	case "bigint", "int", "mediumint", "smallint", "tinyint", "decimal", "year",
+		"char", "varchar", "date", "datetime", "timestamp":
+		return true
+	case "binary":
+		return col.TypeSize == uuidSize
+	default:
+		return false
+	}
+}
+
 // sortedColumns returns a slice of the columns for a given table sorted alphabetically
 func sortedColumns(t *entities.Table) []*entities.Column {
 	ret := make([]*entities.Column, len(t.Columns))
@@ -175,106 +569,156 @@ func sortedColumns(t *entities.Table) []*entities.Column {
 	return ret
 }
 
-func getType(col *entities.Column) string {
+// getType returns the Go type of the given column
+func getType(col *entities.Column) (string, error) {
+	if value, ok := col.Annotation(entities.AnnotationType); ok {
+		return annotatedColumnType(col, value)
+	}
+
 	switch strings.ToLower(col.Type) {
 	case "bigint":
-		if col.Nullable {
-			return NullInt64
-		}
 		if col.Unsigned {
-			return "uint64"
+			return nullable(col, "uint64"), nil
 		}
-		return "int64"
-	case "int":
 		if col.Nullable {
-			return "usql.NullInt"
+			return NullInt64, nil
 		}
+		return "int64", nil
+	case "int":
 		if col.Unsigned {
-			return "uint"
+			return nullable(col, "uint32"), nil
+		}
+		if col.Nullable {
+			return "usql.NullInt", nil
 		}
-		return "int"
+		return "int", nil
 	case "tinyint":
 		if col.TypeSize == 1 {
 			if col.Nullable {
-				return "usql.NullBool"
+				return "usql.NullBool", nil
 			}
-			return "bool"
-		}
-		if col.Nullable {
-			return "usql.NullInt64"
+			return "bool", nil
 		}
 		if col.Unsigned {
-			return "uint8"
+			return nullable(col, "uint8"), nil
 		}
-		return "int8"
+		return nullable(col, "int8"), nil
 	case "smallint":
-		if col.Nullable {
-			return "usql.NullInt64"
-		}
 		if col.Unsigned {
-			return "uint16"
+			return nullable(col, "uint16"), nil
 		}
-		return "int16"
+		return nullable(col, "int16"), nil
 	case "mediumint":
-		if col.Nullable {
-			return "usql.NullInt32"
-		}
 		if col.Unsigned {
-			return "uint32"
+			return nullable(col, "uint32"), nil
 		}
-		return "int32"
-	case "float":
 		if col.Nullable {
-			return "usql.NullFloat64"
+			return "usql.NullInt32", nil
 		}
+		return "int32", nil
+	case "float":
 		if col.TypeSize < 25 {
-			return "float32"
+			return nullable(col, "float32"), nil
 		}
-		return "float64"
-	case "decimal", "double":
 		if col.Nullable {
-			return "usql.NullFloat64"
+			return "usql.NullFloat64", nil
 		}
-		return "float64"
+		return "float64", nil
+	case "decimal":
+		if col.Nullable {
+			return "usql.NullDecimal", nil
+		}
+		return "usql.Decimal", nil
+	case "double":
+		if col.Nullable {
+			return "usql.NullFloat64", nil
+		}
+		return "float64", nil
 	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "string":
 		if col.Nullable {
-			return "usql.NullString"
+			return "usql.NullString", nil
 		}
-		return "string"
+		return "string", nil
 	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
-		return "[]byte"
+		if strings.EqualFold(col.Type, "binary") && col.TypeSize == uuidSize {
+			// BINARY(16) is the conventional way to store a UUID
+			if col.Nullable {
+				return "usql.NullUUID", nil
+			}
+			return "usql.UUID", nil
+		}
+		return "[]byte", nil
 	case "enum":
 		if col.Nullable {
-			return "usql.NullEnum"
+			return "usql.NullEnum", nil
 		}
-		return "usql.Enum"
-	case "year", "date", "datetime", "timestamp":
+		return "usql.Enum", nil
+	case "set":
 		if col.Nullable {
-			return "usql.NullTime"
+			return "usql.NullSet", nil
 		}
-		return "time.Time"
+		return "usql.Set", nil
+	case "bit":
+		if col.Nullable {
+			return "usql.NullBit", nil
+		}
+		return "usql.Bit", nil
+	case "year":
+		return nullable(col, "int16"), nil
+	case "date", "datetime", "timestamp":
+		if col.Nullable {
+			return "usql.NullTime", nil
+		}
+		return "time.Time", nil
 	case "time":
 		if col.Nullable {
-			return "usql.NullDuration"
+			return "usql.NullDuration", nil
 		}
-		return "usql.Duration"
+		return "usql.Duration", nil
 	case "json":
-		return "json.RawMessage"
+		return "json.RawMessage", nil
+	case "point":
+		if col.Nullable {
+			return "usql.NullPoint", nil
+		}
+		return "usql.Point", nil
+	case "geometry", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection":
+		if col.Nullable {
+			return "usql.NullGeometry", nil
+		}
+		return "usql.Geometry", nil
 	default:
-		fmt.Println("Unknown type:", col.Type)
-		return "unknown"
+		return "", fmt.Errorf("%w %q", ErrUnknownType, col.Type)
 	}
 }
 
-func getTags(col *entities.Column) string {
-	tags := "`db:\"" + col.Name
-	if col.InPrimaryKey {
-		tags += ",pk"
+// annotatedColumnType returns the Go type of a column with a `goschema:type` annotation. JSON columns are wrapped
+// in usql.JSON so that the value is marshalled for the caller, other columns use the annotated type as is.
+func annotatedColumnType(col *entities.Column, value string) (string, error) {
+	goType, _, err := annotatedType(value)
+	if err != nil {
+		return "", err
 	}
-	if col.AutoIncrementing {
-		tags += ",autoinc"
+
+	if !strings.EqualFold(col.Type, "json") {
+		return goType, nil
+	}
+	if col.Nullable {
+		return "usql.NullJSON[" + goType + "]", nil
 	}
-	tags += "\"`"
+	return "usql.JSON[" + goType + "]", nil
+}
 
-	return tags
+// nullable returns the given Go type wrapped in usql.Null if the column is nullable
+func nullable(col *entities.Column, goType string) string {
+	if col.Nullable {
+		return "usql.Null[" + goType + "]"
+	}
+
+	return goType
+}
+
+// getTags returns the struct tag of the given column
+func getTags(col *entities.Column) string {
+	return newTagger(nil, getType, nil).structTag(col)
 }
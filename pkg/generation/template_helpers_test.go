@@ -1,6 +1,17 @@
 package generation
 
-import "testing"
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/model"
+	"github.com/pingcap/tidb/pkg/parser/mysql"
+	"github.com/pingcap/tidb/pkg/parser/types"
+)
 
 func TestStructify(t *testing.T) {
 	tests := []struct {
@@ -33,6 +44,26 @@ func TestStructify(t *testing.T) {
 			in:   "single",
 			want: "Single",
 		},
+		{
+			name: "initialism",
+			in:   "user_id",
+			want: "UserID",
+		},
+		{
+			name: "leading_initialisms",
+			in:   "http_url",
+			want: "HTTPURL",
+		},
+		{
+			name: "camel_case_initialism",
+			in:   "ApiKey",
+			want: "APIKey",
+		},
+		{
+			name: "already_structified",
+			in:   "UserID",
+			want: "UserID",
+		},
 	}
 
 	for _, tt := range tests {
@@ -43,3 +74,229 @@ func TestStructify(t *testing.T) {
 		})
 	}
 }
+
+func TestGetType(t *testing.T) {
+	tests := []struct {
+		name    string
+		col     *entities.Column
+		want    string
+		wantErr error
+	}{
+		{
+			name: "bigint",
+			col:  &entities.Column{Type: "bigint"},
+			want: "int64",
+		},
+		{
+			name: "nullable_bigint",
+			col:  &entities.Column{Type: "bigint", Nullable: true},
+			want: "usql.NullInt64",
+		},
+		{
+			name: "nullable_unsigned_bigint",
+			col:  &entities.Column{Type: "bigint", Unsigned: true, Nullable: true},
+			want: "usql.Null[uint64]",
+		},
+		{
+			name: "nullable_unsigned_int",
+			col:  &entities.Column{Type: "int", Unsigned: true, Nullable: true},
+			want: "usql.Null[uint32]",
+		},
+		{
+			name: "unsigned_int",
+			col:  &entities.Column{Type: "int", Unsigned: true},
+			want: "uint32",
+		},
+		{
+			name: "nullable_tinyint",
+			col:  &entities.Column{Type: "tinyint", TypeSize: 4, Nullable: true},
+			want: "usql.Null[int8]",
+		},
+		{
+			name: "nullable_bool",
+			col:  &entities.Column{Type: "tinyint", TypeSize: 1, Nullable: true},
+			want: "usql.NullBool",
+		},
+		{
+			name: "nullable_smallint",
+			col:  &entities.Column{Type: "smallint", Nullable: true},
+			want: "usql.Null[int16]",
+		},
+		{
+			name: "decimal",
+			col:  &entities.Column{Type: "decimal", TypeSize: 10, TypePrecision: 2},
+			want: "usql.Decimal",
+		},
+		{
+			name: "uuid",
+			col:  &entities.Column{Type: "binary", TypeSize: 16},
+			want: "usql.UUID",
+		},
+		{
+			name:    "unknown",
+			col:     &entities.Column{Type: "vector"},
+			wantErr: ErrUnknownType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getType(tt.col)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("getType() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("getType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViewColumns(t *testing.T) {
+	status := types.NewFieldType(mysql.TypeEnum)
+	status.SetElems([]string{"active", "banned"})
+	users := createStmt("users", "id")
+	users.Cols = append(users.Cols, &ast.ColumnDef{
+		Name:    &ast.ColumnName{Name: model.NewCIStr("status")},
+		Tp:      status,
+		Options: []*ast.ColumnOption{{Tp: ast.ColumnOptionNotNull}},
+	})
+
+	field := func(col, alias string) *ast.SelectField {
+		return &ast.SelectField{
+			Expr:   &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: model.NewCIStr(col)}},
+			AsName: model.NewCIStr(alias),
+		}
+	}
+	view := &ast.CreateViewStmt{
+		ViewName: &ast.TableName{Name: model.NewCIStr("user_statuses")},
+		Select: &ast.SelectStmt{
+			From: &ast.TableRefsClause{TableRefs: &ast.Join{
+				Left: &ast.TableSource{Source: &ast.TableName{Name: model.NewCIStr("users")}},
+			}},
+			Fields: &ast.FieldList{Fields: []*ast.SelectField{
+				field("id", ""),
+				field("status", ""),
+				field("status", "account_status"),
+			}},
+		},
+	}
+
+	schema := entities.NewSchema()
+	for _, stmt := range []ast.StmtNode{users, view} {
+		if err := schema.Apply(stmt); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+
+	cfg := &config.Config{
+		Types: []config.TypeRule{{Table: "users", Column: "id", GoType: "UserID"}},
+		Naming: config.Naming{Tables: map[string]*config.TableNaming{
+			"users": {Columns: map[string]string{"status": "State"}},
+		}},
+	}
+	// The view is given first to check that tables take precedence.
+	tables := []*entities.Table{schema.Tables()[1], schema.Tables()[0]}
+	names := newNamer(tables, cfg)
+	m := newTypeMapper(tables, cfg, names)
+
+	var got []string
+	for _, col := range tables[0].Columns {
+		goType, err := m.goType(col)
+		if err != nil {
+			t.Fatalf("goType() error = %v", err)
+		}
+		got = append(got, names.fieldName(col)+" "+goType)
+	}
+	want := []string{"ID UserID", "State UsersState", "AccountStatus UsersState"}
+	if !slices.Equal(got, want) {
+		t.Errorf("view fields = %v, want %v", got, want)
+	}
+
+	if e := m.enums[tables[0].Columns[1]]; e.Table != tables[1] {
+		t.Errorf("enumTypes() status of %q, want users", e.Table.Name)
+	}
+}
+
+func TestIndexFinders(t *testing.T) {
+	id := &entities.Column{Name: "id"}
+	userID := &entities.Column{Name: "user_id"}
+	title := &entities.Column{Name: "title"}
+	ref := &entities.Column{Name: "ref"}
+	yr := &entities.Column{Name: "yr"}
+	body := &entities.Column{Name: "body"}
+
+	table := &entities.Table{
+		Name:       "posts",
+		Columns:    []*entities.Column{id, userID, title, ref, yr, body},
+		PrimaryKey: &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}},
+		Keys: []entities.Key{
+			{Name: "idx_user", Type: "index", Columns: []*entities.Column{userID}},
+			{Name: "idx_user_title", Type: "index", Columns: []*entities.Column{userID, title}},
+			{Name: "uniq_ref_yr", Type: "unique", Columns: []*entities.Column{ref, yr}},
+			{Name: "ft_body", Type: "fulltext", Columns: []*entities.Column{body}},
+		},
+	}
+
+	got := make([]string, 0)
+	for _, f := range indexFinders(table) {
+		name := f.Index.Name + ":"
+		for _, col := range f.Columns {
+			name += " " + col.Name
+		}
+		if f.Prefix() {
+			name += " (prefix)"
+		}
+		got = append(got, name)
+	}
+
+	want := []string{"idx_user: user_id", "idx_user_title: user_id title", "uniq_ref_yr: ref (prefix)"}
+	if !slices.Equal(got, want) {
+		t.Errorf("indexFinders() = %v, want %v", got, want)
+	}
+}
+
+func TestKeysetIndexes(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "bigint"}
+	userID := &entities.Column{Name: "user_id", Type: "bigint"}
+	title := &entities.Column{Name: "title", Type: "varchar"}
+	ref := &entities.Column{Name: "ref", Type: "char"}
+	yr := &entities.Column{Name: "yr", Type: "year"}
+	score := &entities.Column{Name: "score", Type: "double"}
+	slug := &entities.Column{Name: "slug", Type: "varchar", Nullable: true}
+	body := &entities.Column{Name: "body", Type: "text"}
+
+	table := &entities.Table{
+		Name:       "posts",
+		Columns:    []*entities.Column{id, userID, title, ref, yr, score, slug, body},
+		PrimaryKey: &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}},
+		Keys: []entities.Key{
+			{Name: "idx_user", Type: "index", Columns: []*entities.Column{userID}},
+			{Name: "idx_user_id", Type: "index", Columns: []*entities.Column{userID, id}},
+			{Name: "idx_user_title", Type: "index", Columns: []*entities.Column{userID, title}},
+			{Name: "uniq_ref_yr", Type: "unique", Columns: []*entities.Column{ref, yr}},
+			{Name: "idx_score", Type: "index", Columns: []*entities.Column{score}},
+			{Name: "uniq_slug", Type: "unique", Columns: []*entities.Column{slug}},
+			{Name: "ft_body", Type: "fulltext", Columns: []*entities.Column{body}},
+		},
+	}
+
+	got := make([]string, 0)
+	for _, k := range keysetIndexes(table) {
+		name := k.Index.Name + ":"
+		for _, col := range k.Columns {
+			name += " " + col.Name
+		}
+		got = append(got, name)
+	}
+
+	want := []string{"primary: id", "idx_user: user_id id", "idx_user_title: user_id title id", "uniq_ref_yr: ref yr"}
+	if !slices.Equal(got, want) {
+		t.Errorf("keysetIndexes() = %v, want %v", got, want)
+	}
+
+	table.PrimaryKey = nil
+	if got := keysetIndexes(table); len(got) != 0 {
+		t.Errorf("keysetIndexes() without a primary key = %v, want none", got)
+	}
+}
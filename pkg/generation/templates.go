@@ -13,25 +13,57 @@ import (
 
 	"github.com/Masterminds/sprig"
 	"github.com/huandu/xstrings"
+	"github.com/jacobbrewer1/goschema/pkg/config"
 	"github.com/jacobbrewer1/goschema/pkg/entities"
 	"github.com/jacobbrewer1/goschema/pkg/logging"
 )
 
 type templateInfo struct {
-	OutputDir string
-	Table     *entities.Table
+	OutputDir   string
+	PackageName string
+	Table       *entities.Table
 }
 
-func RenderTemplates(tables []*entities.Table, templatesLoc, outputLoc, fileExtensionPrefix string) error {
-	tmpl, err := template.New("model.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).ParseGlob(templatesLoc)
+// RenderTemplates renders the templates matching the given glob. The package name defaults to the name of the
+// output directory when empty.
+func RenderTemplates(cfg *config.Config, tables []*entities.Table, templatesLoc, outputLoc, packageName, fileExtensionPrefix string) error {
+	names := newNamer(tables, cfg)
+	if err := names.validate(tables); err != nil {
+		return err
+	}
+
+	types := newTypeMapper(tables, cfg, names)
+	if err := types.validate(tables); err != nil {
+		return err
+	}
+
+	versions := newVersioner(cfg)
+	if err := versions.validate(tables); err != nil {
+		return err
+	}
+
+	timestamps := newTimestamper(types.goType)
+	if err := timestamps.validate(tables); err != nil {
+		return err
+	}
+
+	tags := newTagger(cfg, types.goType, managedColumns(tables, versions, timestamps))
+	if err := tags.validate(tables); err != nil {
+		return err
+	}
+
+	funcs := tableHelpers(types, names, tags, newRelations(tables), newSoftDeleter(cfg, types.goType), versions, timestamps, newAuditor(cfg), signatures{legacy: cfg != nil && cfg.LegacySignatures})
+
+	tmpl, err := template.New("model.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseGlob(templatesLoc)
 	if err != nil {
 		return fmt.Errorf("error parsing templates: %w", err)
 	}
 
 	for _, t := range tables {
 		if err = generate(&templateInfo{
-			OutputDir: outputLoc,
-			Table:     t,
+			OutputDir:   outputLoc,
+			PackageName: outputPackage(outputLoc, packageName),
+			Table:       t,
 		}, tmpl, outputLoc, fileExtensionPrefix); err != nil {
 			return fmt.Errorf("error generating template: %w", err)
 		}
@@ -40,44 +72,76 @@ func RenderTemplates(tables []*entities.Table, templatesLoc, outputLoc, fileExte
 	return nil
 }
 
-// RenderWithTemplates renders templates that are provided as embedded files
-func RenderWithTemplates(fs embed.FS, tables []*entities.Table, outputLoc, fileExtensionPrefix string) error {
-	tmpl, err := template.New("model.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).ParseFS(fs, "templates/*.tmpl")
+// RenderWithTemplates renders templates that are provided as embedded files. The package name defaults to the name
+// of the output directory when empty.
+func RenderWithTemplates(cfg *config.Config, fs embed.FS, tables []*entities.Table, outputLoc, packageName, fileExtensionPrefix string) error {
+	names := newNamer(tables, cfg)
+	if err := names.validate(tables); err != nil {
+		return err
+	}
+
+	types := newTypeMapper(tables, cfg, names)
+	if err := types.validate(tables); err != nil {
+		return err
+	}
+
+	versions := newVersioner(cfg)
+	if err := versions.validate(tables); err != nil {
+		return err
+	}
+
+	timestamps := newTimestamper(types.goType)
+	if err := timestamps.validate(tables); err != nil {
+		return err
+	}
+
+	tags := newTagger(cfg, types.goType, managedColumns(tables, versions, timestamps))
+	if err := tags.validate(tables); err != nil {
+		return err
+	}
+
+	funcs := tableHelpers(types, names, tags, newRelations(tables), newSoftDeleter(cfg, types.goType), versions, timestamps, newAuditor(cfg), signatures{legacy: cfg != nil && cfg.LegacySignatures})
+
+	tmpl, err := template.New("model.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFS(fs, "templates/*.tmpl")
 	if err != nil {
 		return fmt.Errorf("error parsing templates: %w", err)
 	}
 
 	for _, t := range tables {
 		if err := generate(&templateInfo{
-			OutputDir: outputLoc,
-			Table:     t,
+			OutputDir:   outputLoc,
+			PackageName: outputPackage(outputLoc, packageName),
+			Table:       t,
 		}, tmpl, outputLoc, fileExtensionPrefix); err != nil {
 			return fmt.Errorf("error generating template: %w", err)
 		}
 	}
 
-	if err := renderHelpers(fs, outputLoc, fileExtensionPrefix); err != nil {
+	if err := renderHelpers(fs, funcs, newAuditor(cfg), outputLoc, packageName, fileExtensionPrefix); err != nil {
 		return fmt.Errorf("error rendering helpers: %w", err)
 	}
 
 	return nil
 }
 
-func renderHelpers(fs embed.FS, outputLoc, fileExtensionPrefix string) error {
+// renderHelpers renders the files shared by the models with the given template helpers. The audit file is only
+// rendered when the writes are audited.
+func renderHelpers(fs embed.FS, funcs template.FuncMap, audits auditor, outputLoc, packageName, fileExtensionPrefix string) error {
 	wg := new(sync.WaitGroup)
 	errs := new(sync.Map)
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		tmpl, err := template.New("db.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).ParseFS(fs, "templates/db.tmpl")
+		tmpl, err := template.New("db.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFS(fs, "templates/db.tmpl")
 		if err != nil {
 			errs.Store("error parsing db template", err)
 			return
 		}
 
 		if err := generate(&templateInfo{
-			OutputDir: outputLoc,
+			OutputDir:   outputLoc,
+			PackageName: outputPackage(outputLoc, packageName),
 		}, tmpl, outputLoc, fileExtensionPrefix); err != nil {
 			errs.Store("error generating db template", err)
 		}
@@ -86,14 +150,15 @@ func renderHelpers(fs embed.FS, outputLoc, fileExtensionPrefix string) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		tmpl, err := template.New("errors.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).ParseFS(fs, "templates/errors.tmpl")
+		tmpl, err := template.New("errors.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFS(fs, "templates/errors.tmpl")
 		if err != nil {
 			errs.Store("error parsing errors template", err)
 			return
 		}
 
 		if err := generate(&templateInfo{
-			OutputDir: outputLoc,
+			OutputDir:   outputLoc,
+			PackageName: outputPackage(outputLoc, packageName),
 		}, tmpl, outputLoc, fileExtensionPrefix); err != nil {
 			errs.Store("error generating helpers template", err)
 		}
@@ -102,14 +167,15 @@ func renderHelpers(fs embed.FS, outputLoc, fileExtensionPrefix string) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		tmpl, err := template.New("helpers.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).ParseFS(fs, "templates/helpers.tmpl")
+		tmpl, err := template.New("helpers.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFS(fs, "templates/helpers.tmpl")
 		if err != nil {
 			errs.Store("error parsing helpers template", err)
 			return
 		}
 
 		if err := generate(&templateInfo{
-			OutputDir: outputLoc,
+			OutputDir:   outputLoc,
+			PackageName: outputPackage(outputLoc, packageName),
 		}, tmpl, outputLoc, fileExtensionPrefix); err != nil {
 			errs.Store("error generating helpers template", err)
 		}
@@ -118,19 +184,39 @@ func renderHelpers(fs embed.FS, outputLoc, fileExtensionPrefix string) error {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		tmpl, err := template.New("metrics.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).ParseFS(fs, "templates/metrics.tmpl")
+		tmpl, err := template.New("metrics.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFS(fs, "templates/metrics.tmpl")
 		if err != nil {
 			errs.Store("error parsing metrics template", err)
 			return
 		}
 
 		if err := generate(&templateInfo{
-			OutputDir: outputLoc,
+			OutputDir:   outputLoc,
+			PackageName: outputPackage(outputLoc, packageName),
 		}, tmpl, outputLoc, fileExtensionPrefix); err != nil {
 			errs.Store("error generating metrics template", err)
 		}
 	}()
 
+	if audits.auditEnabled() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tmpl, err := template.New("audit.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFS(fs, "templates/audit.tmpl")
+			if err != nil {
+				errs.Store("error parsing audit template", err)
+				return
+			}
+
+			if err := generate(&templateInfo{
+				OutputDir:   outputLoc,
+				PackageName: outputPackage(outputLoc, packageName),
+			}, tmpl, outputLoc, fileExtensionPrefix); err != nil {
+				errs.Store("error generating audit template", err)
+			}
+		}()
+	}
+
 	wg.Wait()
 
 	errs.Range(func(key, value any) bool {
@@ -150,6 +236,15 @@ func renderHelpers(fs embed.FS, outputLoc, fileExtensionPrefix string) error {
 	return nil
 }
 
+// outputPackage returns the name of the generated package, which defaults to the name of the output directory
+func outputPackage(outputLoc, packageName string) string {
+	if packageName != "" {
+		return packageName
+	}
+
+	return xstrings.ToSnakeCase(filepath.Base(outputLoc))
+}
+
 func generate(t *templateInfo, tmpl *template.Template, outputLoc, fileExtensionPrefix string) error {
 	ext := ".go"
 	if fileExtensionPrefix != "" {
@@ -0,0 +1,455 @@
+package generation
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// renderModel renders the model of the table with the templates of the repository and parses the generated file
+func renderModel(t *testing.T, cfg *config.Config, table *entities.Table) (*token.FileSet, *ast.File) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := RenderTemplates(cfg, []*entities.Table{table}, "../../templates/*.tmpl", dir, "models", ""); err != nil {
+		t.Fatalf("RenderTemplates() error = %v", err)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filepath.Join(dir, table.Name+".go"), nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	return fset, f
+}
+
+// findFunc returns the named function or method of the file
+func findFunc(t *testing.T, f *ast.File, name string) *ast.FuncDecl {
+	t.Helper()
+
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name {
+			return fn
+		}
+	}
+
+	t.Fatalf("function %s is not generated", name)
+	return nil
+}
+
+// funcSource returns the source of the named function or method of the file
+func funcSource(t *testing.T, fset *token.FileSet, f *ast.File, name string) string {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	if err := printer.Fprint(buf, fset, findFunc(t, f, name)); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestIterator(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int", InPrimaryKey: true, AutoIncrementing: true}
+	email := &entities.Column{Name: "email", Type: "varchar", TypeSize: 255}
+	users := &entities.Table{
+		Name:       "users",
+		Columns:    []*entities.Column{id, email},
+		PrimaryKey: &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}},
+	}
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want []string
+	}{
+		{
+			name: "context",
+			cfg:  new(config.Config),
+			want: []string{
+				"func IterUsers(ctx context.Context, db DB, filters ...any) iter.Seq2[*Users, error]",
+				"rows, err := db.QueryxContext(ctx, sqlstr, args...)",
+			},
+		},
+		{
+			name: "legacy",
+			cfg:  &config.Config{LegacySignatures: true},
+			want: []string{
+				"func IterUsers(db DB, filters ...any) iter.Seq2[*Users, error]",
+				"rows, err := db.Queryx(sqlstr, args...)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, f := renderModel(t, tt.cfg, users)
+
+			imported := false
+			for _, spec := range f.Imports {
+				if path, _ := strconv.Unquote(spec.Path.Value); path == "iter" {
+					imported = true
+				}
+			}
+			if !imported {
+				t.Error("generated file does not import iter")
+			}
+
+			src := funcSource(t, fset, f, "IterUsers")
+			want := append(tt.want,
+				"SELECT t.`id`, t.`email`",
+				"clause, args := filterClause(filters, \"\")",
+				// The rows are closed however the loop ends, and iteration stops when the loop breaks.
+				"defer rows.Close()",
+				"if !yield(m, nil) {\n\t\t\t\treturn\n\t\t\t}",
+				"if err := rows.StructScan(m); err != nil {",
+				"if err := rows.Err(); err != nil {",
+			)
+			for _, w := range want {
+				if !strings.Contains(src, w) {
+					t.Errorf("IterUsers does not contain %q:\n%s", w, src)
+				}
+			}
+		})
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int", InPrimaryKey: true, AutoIncrementing: true}
+	body := &entities.Column{Name: "body", Type: "varchar", TypeSize: 255}
+	notes := &entities.Table{
+		Name:       "notes",
+		Columns:    []*entities.Column{id, body},
+		PrimaryKey: &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}},
+	}
+
+	tests := []struct {
+		name   string
+		legacy bool
+		want   []string
+		db     []string
+	}{
+		{
+			name: "context",
+			want: []string{
+				"func (m *Notes) Insert(ctx context.Context, db DB) error {",
+				"res, err := db.ExecContext(ctx, sqlstr, m.Body)",
+			},
+			db: []string{
+				"ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)",
+				"GetContext(ctx context.Context, dest any, query string, args ...any) error",
+				"BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)",
+			},
+		},
+		{
+			name:   "legacy",
+			legacy: true,
+			want: []string{
+				"func (m *Notes) Insert(db DB) error {",
+				"res, err := db.Exec(sqlstr, m.Body)",
+			},
+			db: []string{
+				"Exec(string, ...any) (sql.Result, error)",
+				"Get(dest any, query string, args ...any) error",
+				"Beginx() (*sqlx.Tx, error)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, f := renderModel(t, &config.Config{LegacySignatures: tt.legacy}, notes)
+			src := funcSource(t, fset, f, "Insert")
+			for _, w := range tt.want {
+				if !strings.Contains(src, w) {
+					t.Errorf("Insert does not contain %q:\n%s", w, src)
+				}
+			}
+
+			funcs := tableHelpers(newTypeMapper(nil, nil, defaultNames), defaultNames, newTagger(nil, getType, nil), newRelations(nil),
+				newSoftDeleter(nil, getType), newVersioner(nil), newTimestamper(getType), newAuditor(nil), signatures{legacy: tt.legacy})
+			tmpl, err := template.New("db.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFiles("../../templates/db.tmpl")
+			if err != nil {
+				t.Fatal(err)
+			}
+			buf := new(bytes.Buffer)
+			if err := tmpl.Execute(buf, &templateInfo{PackageName: "models"}); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), "db.go", buf.Bytes(), 0); err != nil {
+				t.Fatalf("generated code does not parse: %v", err)
+			}
+			for _, w := range tt.db {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("db.go does not contain %q:\n%s", w, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestInsertWithUpdate(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int", InPrimaryKey: true, AutoIncrementing: true}
+	title := &entities.Column{Name: "title", Type: "varchar", TypeSize: 255}
+	revision := &entities.Column{Name: "revision", Type: "int", Unsigned: true}
+	createdAt := &entities.Column{Name: "created_at", Type: "datetime"}
+	posts := &entities.Table{
+		Name:       "posts",
+		Columns:    []*entities.Column{id, title, revision, createdAt},
+		PrimaryKey: &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}},
+	}
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want string
+	}{
+		{
+			name: "not_versioned",
+			cfg:  new(config.Config),
+			want: "\"`title` = VALUES(`title`), `revision` = VALUES(`revision`)\"",
+		},
+		{
+			name: "versioned",
+			cfg:  &config.Config{Version: "revision"},
+			want: "\"`title` = VALUES(`title`), `revision` = `revision` + 1\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, f := renderModel(t, tt.cfg, posts)
+
+			if src := funcSource(t, fset, f, "InsertWithUpdate"); !strings.Contains(src, tt.want) {
+				t.Errorf("InsertWithUpdate does not contain %s:\n%s", tt.want, src)
+			}
+		})
+	}
+}
+
+// calls returns the names of the functions and methods called within the node that are in names, in the order they
+// are called
+func calls(node ast.Node, names ...string) []string {
+	ret := make([]string, 0)
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		var name string
+		switch f := call.Fun.(type) {
+		case *ast.Ident:
+			name = f.Name
+		case *ast.SelectorExpr:
+			name = f.Sel.Name
+		}
+		if slices.Contains(names, name) {
+			ret = append(ret, name)
+		}
+		return true
+	})
+	return ret
+}
+
+// guarded returns the names of the functions and methods in names whose error is returned by the function as soon as
+// it is called, with `if err := f(...); err != nil { return err }`
+func guarded(fn *ast.FuncDecl, names ...string) []string {
+	ret := make([]string, 0)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		stmt, ok := n.(*ast.IfStmt)
+		if !ok || len(stmt.Body.List) != 1 {
+			return true
+		}
+		init, ok := stmt.Init.(*ast.AssignStmt)
+		if !ok || len(init.Rhs) != 1 {
+			return true
+		}
+		if _, ok := stmt.Body.List[0].(*ast.ReturnStmt); !ok {
+			return true
+		}
+
+		if name := calls(init.Rhs[0], names...); len(name) == 1 {
+			ret = append(ret, name[0])
+		}
+		return true
+	})
+	return ret
+}
+
+func TestHooks(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int", InPrimaryKey: true, AutoIncrementing: true}
+	body := &entities.Column{Name: "body", Type: "varchar", TypeSize: 255}
+	revision := &entities.Column{Name: "revision", Type: "int", Unsigned: true}
+	deletedAt := &entities.Column{Name: "deleted_at", Type: "datetime", Nullable: true}
+	pk := &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}}
+	comments := &entities.Table{Name: "comments", Columns: []*entities.Column{id, body, revision, deletedAt}, PrimaryKey: pk}
+	notes := &entities.Table{Name: "notes", Columns: []*entities.Column{id, body}, PrimaryKey: pk}
+
+	save := []string{"preSave", "ExecContext", "postSave"}
+	tests := []struct {
+		table  *entities.Table
+		method string
+		want   []string
+	}{
+		{table: notes, method: "Insert", want: save},
+		{table: notes, method: "InsertWithUpdate", want: save},
+		{table: notes, method: "Update", want: save},
+		{table: notes, method: "Patch", want: save},
+		{table: notes, method: "InsertManyNotes", want: save},
+		{table: notes, method: "Delete", want: []string{"preDelete", "ExecContext", "postDelete"}},
+		{table: comments, method: "Update", want: save},
+		{table: comments, method: "Delete", want: []string{"preDelete", "preSave", "ExecContext", "postSave", "postDelete"}},
+		{table: comments, method: "HardDelete", want: []string{"preDelete", "ExecContext", "postDelete"}},
+		{table: comments, method: "Restore", want: save},
+	}
+
+	cfg := &config.Config{Version: "revision"}
+	files := make(map[*entities.Table]*ast.File)
+	for _, tt := range tests {
+		t.Run(tt.table.Name+"_"+tt.method, func(t *testing.T) {
+			f, ok := files[tt.table]
+			if !ok {
+				_, f = renderModel(t, cfg, tt.table)
+				files[tt.table] = f
+			}
+			fn := findFunc(t, f, tt.method)
+
+			// The write is between the pre and post hooks.
+			if got := calls(fn.Body, "preSave", "postSave", "preDelete", "postDelete", "ExecContext"); !slices.Equal(got, tt.want) {
+				t.Errorf("%s calls %v, want %v", tt.method, got, tt.want)
+			}
+
+			// An error of a pre hook stops the write.
+			want := make([]string, 0)
+			for _, name := range tt.want {
+				if strings.HasPrefix(name, "pre") {
+					want = append(want, name)
+				}
+			}
+			if got := guarded(fn, "preSave", "preDelete"); !slices.Equal(got, want) {
+				t.Errorf("%s returns the errors of %v, want %v", tt.method, got, want)
+			}
+		})
+	}
+}
+
+func TestAuditTx(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int", InPrimaryKey: true, AutoIncrementing: true}
+	body := &entities.Column{Name: "body", Type: "varchar", TypeSize: 255}
+	deletedAt := &entities.Column{Name: "deleted_at", Type: "datetime", Nullable: true}
+	pk := &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}}
+	comments := &entities.Table{Name: "comments", Columns: []*entities.Column{id, body, deletedAt}, PrimaryKey: pk}
+	notes := &entities.Table{Name: "notes", Columns: []*entities.Column{id, body}, PrimaryKey: pk}
+
+	tests := []struct {
+		table  *entities.Table
+		method string
+		call   string
+	}{
+		{table: notes, method: "Insert", call: "Insert"},
+		{table: notes, method: "InsertNotesWithPK", call: "InsertNotesWithPK"},
+		{table: notes, method: "InsertWithUpdate", call: "InsertWithUpdate"},
+		{table: notes, method: "InsertManyNotes", call: "InsertManyNotes"},
+		{table: notes, method: "Update", call: "Update"},
+		{table: notes, method: "Patch", call: "Patch"},
+		{table: notes, method: "Delete", call: "Delete"},
+		{table: comments, method: "Delete", call: "Delete"},
+		{table: comments, method: "HardDelete", call: "HardDelete"},
+		{table: comments, method: "Restore", call: "Restore"},
+	}
+
+	files := make(map[*entities.Table]*ast.File)
+	plain := make(map[*entities.Table]*ast.File)
+	for _, tt := range tests {
+		t.Run(tt.table.Name+"_"+tt.method, func(t *testing.T) {
+			f, ok := files[tt.table]
+			if !ok {
+				_, f = renderModel(t, &config.Config{Audit: true}, tt.table)
+				_, plain[tt.table] = renderModel(t, new(config.Config), tt.table)
+				files[tt.table] = f
+			}
+
+			// The write runs again within a transaction of the db before any hook or statement.
+			fn := findFunc(t, f, tt.method)
+			got := calls(fn.Body, "auditTx", tt.call, "preSave", "preDelete", "ExecContext")
+			if len(got) < 2 || got[0] != "auditTx" || got[1] != tt.call {
+				t.Errorf("%s calls %v, want auditTx calling %s first", tt.method, got, tt.call)
+			}
+
+			if got := calls(findFunc(t, plain[tt.table], tt.method).Body, "auditTx"); len(got) > 0 {
+				t.Errorf("%s of a table that is not audited calls auditTx", tt.method)
+			}
+		})
+	}
+}
+
+func TestHookHelpers(t *testing.T) {
+	funcs := tableHelpers(newTypeMapper(nil, nil, defaultNames), defaultNames, newTagger(nil, getType, nil), newRelations(nil),
+		newSoftDeleter(nil, getType), newVersioner(nil), newTimestamper(getType), newAuditor(nil), signatures{})
+	tmpl, err := template.New("helpers.tmpl").Funcs(sprig.TxtFuncMap()).Funcs(Helpers).Funcs(funcs).ParseFiles("../../templates/helpers.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, &templateInfo{PackageName: "models"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "helpers.go", buf, 0)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+
+	// Every hook of a model takes the context and db of the write.
+	for _, iface := range []string{"PreSaveable", "PostSaveable", "PreDeletable", "PostDeletable"} {
+		obj := f.Scope.Lookup(iface)
+		if obj == nil {
+			t.Fatalf("%s is not generated", iface)
+		}
+
+		buf.Reset()
+		if err := printer.Fprint(buf, fset, obj.Decl.(*ast.TypeSpec).Type); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "(ctx context.Context, db DB) error") {
+			t.Errorf("%s = %s, want a method taking the context and db", iface, buf.String())
+		}
+	}
+
+	// The hook of the model runs before the registered hooks, and an error of either is returned.
+	for helper, hook := range map[string]string{
+		"preSave":    "PreSave",
+		"postSave":   "PostSave",
+		"preDelete":  "PreDelete",
+		"postDelete": "PostDelete",
+	} {
+		fn := findFunc(t, f, helper)
+		if got, want := calls(fn.Body, hook, "runHooks"), []string{hook, "runHooks"}; !slices.Equal(got, want) {
+			t.Errorf("%s calls %v, want %v", helper, got, want)
+		}
+		if got, want := guarded(fn, hook), []string{hook}; !slices.Equal(got, want) {
+			t.Errorf("%s returns the errors of %v, want %v", helper, got, want)
+		}
+		if got := funcSource(t, fset, f, helper); !strings.Contains(got, "return runHooks(ctx, db, ") {
+			t.Errorf("%s does not return the error of the registered hooks:\n%s", helper, got)
+		}
+	}
+
+	// Registered hooks run in the order they are registered, and stop at the first error.
+	if got, want := guarded(findFunc(t, f, "runHooks"), "h"), []string{"h"}; !slices.Equal(got, want) {
+		t.Errorf("runHooks returns the errors of %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package generation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// timestamper finds the columns that the generated writes set to the current time. A column is used if it is
+// annotated with `goschema:created_at` or `goschema:updated_at`, or else if it has that name.
+type timestamper struct {
+	goType func(col *entities.Column) (string, error)
+}
+
+func newTimestamper(goType func(col *entities.Column) (string, error)) timestamper {
+	return timestamper{goType: goType}
+}
+
+// createdAtColumn returns the column that is set when a row of the table is inserted, or nil
+func (ts timestamper) createdAtColumn(t *entities.Table) *entities.Column {
+	return ts.column(t, entities.AnnotationCreatedAt)
+}
+
+// updatedAtColumn returns the column that is set whenever a row of the table is written, or nil
+func (ts timestamper) updatedAtColumn(t *entities.Table) *entities.Column {
+	return ts.column(t, entities.AnnotationUpdatedAt)
+}
+
+// column returns the timestamp column of the table with the given annotation, which is also the default name of the
+// column
+func (ts timestamper) column(t *entities.Table, annotation string) *entities.Column {
+	if t.IsView {
+		return nil
+	}
+
+	var col *entities.Column
+	for _, c := range t.Columns {
+		if _, ok := c.Annotation(annotation); ok {
+			col = c
+			break
+		}
+	}
+	if col == nil {
+		col, _ = findColumn(t, annotation)
+	}
+
+	if col == nil || !ts.isTimestampColumn(col) {
+		return nil
+	}
+
+	return col
+}
+
+// isTimestampColumn returns true if the column can be set to the current time
+func (ts timestamper) isTimestampColumn(col *entities.Column) bool {
+	if col.Generated {
+		return false
+	}
+
+	switch strings.ToLower(col.Type) {
+	case "datetime", "timestamp", "date":
+	default:
+		return false
+	}
+
+	goType, err := ts.goType(col)
+	return err == nil && (goType == "time.Time" || goType == "usql.NullTime")
+}
+
+// validate checks that the columns annotated as timestamps of the given tables can be used
+func (ts timestamper) validate(tables []*entities.Table) error {
+	for _, t := range tables {
+		for _, annotation := range []string{entities.AnnotationCreatedAt, entities.AnnotationUpdatedAt} {
+			annotated := 0
+			for _, col := range t.Columns {
+				if _, ok := col.Annotation(annotation); !ok {
+					continue
+				}
+
+				annotated++
+				if annotated > 1 {
+					return fmt.Errorf("table %q column %q: %w: the table has more than one %s column", t.Name, col.Name, ErrInvalidTimestamp, annotation)
+				}
+				if !ts.isTimestampColumn(col) {
+					return fmt.Errorf("table %q column %q: %w: must be a DATE, DATETIME or TIMESTAMP", t.Name, col.Name, ErrInvalidTimestamp)
+				}
+			}
+		}
+	}
+
+	return nil
+}
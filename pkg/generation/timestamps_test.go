@@ -0,0 +1,101 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestTimestamperColumns(t *testing.T) {
+	created := map[string]string{entities.AnnotationCreatedAt: ""}
+	updated := map[string]string{entities.AnnotationUpdatedAt: ""}
+
+	tests := []struct {
+		name        string
+		table       *entities.Table
+		wantCreated string
+		wantUpdated string
+	}{
+		{
+			name:        "named",
+			table:       &entities.Table{Columns: []*entities.Column{{Name: "Created_At", Type: "datetime"}, {Name: "updated_at", Type: "timestamp", Nullable: true}}},
+			wantCreated: "Created_At",
+			wantUpdated: "updated_at",
+		},
+		{
+			name: "annotated",
+			table: &entities.Table{Columns: []*entities.Column{
+				{Name: "created_at", Type: "datetime"},
+				{Name: "inserted", Type: "date", Annotations: created},
+				{Name: "modified", Type: "datetime", Annotations: updated},
+			}},
+			wantCreated: "inserted",
+			wantUpdated: "modified",
+		},
+		{
+			name:  "not_a_time",
+			table: &entities.Table{Columns: []*entities.Column{{Name: "created_at", Type: "bigint"}, {Name: "updated_at", Type: "varchar"}}},
+		},
+		{
+			name:  "generated",
+			table: &entities.Table{Columns: []*entities.Column{{Name: "updated_at", Type: "datetime", Generated: true}}},
+		},
+		{
+			name:  "type_annotation",
+			table: &entities.Table{Columns: []*entities.Column{{Name: "updated_at", Type: "datetime", Annotations: map[string]string{entities.AnnotationType: "example.com/pkg.Time"}}}},
+		},
+		{
+			name:  "view",
+			table: &entities.Table{IsView: true, Columns: []*entities.Column{{Name: "created_at", Type: "datetime"}, {Name: "updated_at", Type: "datetime"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTimestamper(getType)
+
+			name := func(col *entities.Column) string {
+				if col == nil {
+					return ""
+				}
+				return col.Name
+			}
+			if got := name(ts.createdAtColumn(tt.table)); got != tt.wantCreated {
+				t.Errorf("createdAtColumn() = %q, want %q", got, tt.wantCreated)
+			}
+			if got := name(ts.updatedAtColumn(tt.table)); got != tt.wantUpdated {
+				t.Errorf("updatedAtColumn() = %q, want %q", got, tt.wantUpdated)
+			}
+		})
+	}
+}
+
+func TestTimestamperValidate(t *testing.T) {
+	created := map[string]string{entities.AnnotationCreatedAt: ""}
+	updated := map[string]string{entities.AnnotationUpdatedAt: ""}
+
+	tests := []struct {
+		name    string
+		columns []*entities.Column
+		wantErr bool
+	}{
+		{name: "valid", columns: []*entities.Column{{Name: "inserted", Type: "datetime", Annotations: created}, {Name: "modified", Type: "timestamp", Annotations: updated}}},
+		{name: "not_a_time", columns: []*entities.Column{{Name: "modified", Type: "int", Annotations: updated}}, wantErr: true},
+		{
+			name: "more_than_one",
+			columns: []*entities.Column{
+				{Name: "inserted", Type: "datetime", Annotations: created},
+				{Name: "created", Type: "datetime", Annotations: created},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTimestamper(getType).validate([]*entities.Table{{Name: "posts", Columns: tt.columns}})
+			if tt.wantErr != errors.Is(err, ErrInvalidTimestamp) {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package generation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+// versioner finds the columns that version the rows of a table for optimistic locking. A column annotated with
+// `goschema:version` is used before the column named in the config.
+type versioner struct {
+	column string
+}
+
+func newVersioner(cfg *config.Config) versioner {
+	v := versioner{}
+	if cfg != nil {
+		v.column = cfg.Version
+	}
+
+	return v
+}
+
+// versionColumn returns the version column of the table, or nil if the table is not versioned. Versions need a key
+// to update the row by, and a NOT NULL integer column that is not part of the key.
+func (v versioner) versionColumn(t *entities.Table) *entities.Column {
+	if t.IsView {
+		return nil
+	}
+
+	var col *entities.Column
+	for _, c := range t.Columns {
+		if _, ok := c.Annotation(entities.AnnotationVersion); ok {
+			col = c
+			break
+		}
+	}
+	if col == nil && v.column != "" {
+		col, _ = findColumn(t, v.column)
+	}
+
+	if col == nil || !isVersionColumn(col) {
+		return nil
+	}
+
+	keys := identityColumns(t)
+	if len(keys) == 0 || slices.Contains(keys, col) {
+		return nil
+	}
+
+	return col
+}
+
+// isVersionColumn returns true if the column can hold the version of a row
+func isVersionColumn(col *entities.Column) bool {
+	if col.Nullable || col.Generated || col.AutoIncrementing {
+		return false
+	}
+	if _, ok := col.Annotation(entities.AnnotationType); ok {
+		return false
+	}
+
+	switch strings.ToLower(col.Type) {
+	case "bigint", "int", "mediumint", "smallint":
+		return true
+	case "tinyint":
+		// TINYINT(1) is a bool
+		return col.TypeSize != 1
+	default:
+		return false
+	}
+}
+
+// validate checks that the columns annotated as versions of the given tables can be used
+func (v versioner) validate(tables []*entities.Table) error {
+	for _, t := range tables {
+		annotated := 0
+		for _, col := range t.Columns {
+			if _, ok := col.Annotation(entities.AnnotationVersion); !ok {
+				continue
+			}
+
+			annotated++
+			if annotated > 1 {
+				return fmt.Errorf("table %q column %q: %w: the table has more than one version", t.Name, col.Name, ErrInvalidVersion)
+			}
+			if !isVersionColumn(col) {
+				return fmt.Errorf("table %q column %q: %w: must be a NOT NULL integer", t.Name, col.Name, ErrInvalidVersion)
+			}
+		}
+	}
+
+	return nil
+}
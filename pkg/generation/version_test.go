@@ -0,0 +1,112 @@
+package generation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jacobbrewer1/goschema/pkg/config"
+	"github.com/jacobbrewer1/goschema/pkg/entities"
+)
+
+func TestVersionColumn(t *testing.T) {
+	id := &entities.Column{Name: "id", Type: "int"}
+	pk := &entities.Key{Name: "primary", Type: "primary", Columns: []*entities.Column{id}}
+	annotated := map[string]string{entities.AnnotationVersion: ""}
+
+	tests := []struct {
+		name  string
+		cfg   *config.Config
+		table *entities.Table
+		want  string
+	}{
+		{
+			name:  "annotated",
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "revision", Type: "int", Annotations: annotated}}, PrimaryKey: pk},
+			want:  "revision",
+		},
+		{
+			name:  "configured",
+			cfg:   &config.Config{Version: "lock_version"},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "Lock_Version", Type: "bigint", Unsigned: true}}, PrimaryKey: pk},
+			want:  "Lock_Version",
+		},
+		{
+			name:  "annotation_first",
+			cfg:   &config.Config{Version: "version"},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "version", Type: "int"}, {Name: "revision", Type: "int", Annotations: annotated}}, PrimaryKey: pk},
+			want:  "revision",
+		},
+		{
+			name:  "not_configured",
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "version", Type: "int"}}, PrimaryKey: pk},
+		},
+		{
+			name:  "nullable",
+			cfg:   &config.Config{Version: "version"},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "version", Type: "int", Nullable: true}}, PrimaryKey: pk},
+		},
+		{
+			name:  "not_an_integer",
+			cfg:   &config.Config{Version: "version"},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "version", Type: "varchar"}}, PrimaryKey: pk},
+		},
+		{
+			name:  "key",
+			cfg:   &config.Config{Version: "id"},
+			table: &entities.Table{Columns: []*entities.Column{id}, PrimaryKey: pk},
+		},
+		{
+			name:  "no_key",
+			cfg:   &config.Config{Version: "version"},
+			table: &entities.Table{Columns: []*entities.Column{id, {Name: "version", Type: "int"}}},
+		},
+		{
+			name:  "view",
+			cfg:   &config.Config{Version: "version"},
+			table: &entities.Table{IsView: true, Columns: []*entities.Column{id, {Name: "version", Type: "int"}}, PrimaryKey: pk},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newVersioner(tt.cfg).versionColumn(tt.table)
+
+			name := ""
+			if got != nil {
+				name = got.Name
+			}
+			if name != tt.want {
+				t.Errorf("versionColumn() = %q, want %q", name, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionerValidate(t *testing.T) {
+	annotated := map[string]string{entities.AnnotationVersion: ""}
+
+	tests := []struct {
+		name    string
+		columns []*entities.Column
+		wantErr bool
+	}{
+		{name: "valid", columns: []*entities.Column{{Name: "revision", Type: "int", Annotations: annotated}}},
+		{name: "not_an_integer", columns: []*entities.Column{{Name: "revision", Type: "datetime", Annotations: annotated}}, wantErr: true},
+		{name: "bool", columns: []*entities.Column{{Name: "revision", Type: "tinyint", TypeSize: 1, Annotations: annotated}}, wantErr: true},
+		{
+			name: "more_than_one",
+			columns: []*entities.Column{
+				{Name: "revision", Type: "int", Annotations: annotated},
+				{Name: "version", Type: "int", Annotations: annotated},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newVersioner(nil).validate([]*entities.Table{{Name: "posts", Columns: tt.columns}})
+			if tt.wantErr != errors.Is(err, ErrInvalidVersion) {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
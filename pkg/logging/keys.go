@@ -10,6 +10,12 @@ const (
 	// KeyFile is the key for a file
 	KeyFile = "file"
 
+	// KeyLine is the key for a line number
+	KeyLine = "line"
+
+	// KeyTable is the key for a table
+	KeyTable = "table"
+
 	// KeyPath is the key for a path
 	KeyPath = "path"
 
@@ -19,6 +25,18 @@ const (
 	// KeySqlLoc is the key for the SQL location
 	KeySqlLoc = "sql_location"
 
+	// KeyMigrationLoc is the key for the migrations location
+	KeyMigrationLoc = "migration_location"
+
+	// KeyConfigLoc is the key for the config file location
+	KeyConfigLoc = "config_location"
+
+	// KeyTarget is the key for a generation target
+	KeyTarget = "target"
+
+	// KeyEnvironment is the key for a migration environment
+	KeyEnvironment = "environment"
+
 	// KeyTmplLoc is the key for the templates location
 	KeyTmplLoc = "templates_location"
 
@@ -14,8 +14,13 @@ const (
 )
 
 func ConnectDB() (*sqlx.DB, error) {
+	return ConnectDSN(os.Getenv(DbEnvVar))
+}
+
+// ConnectDSN opens a connection to the database with the given data source name.
+func ConnectDSN(dsn string) (*sqlx.DB, error) {
 	// Get the connection string.
-	connStr := getConnectionStr()
+	connStr := getConnectionStr(dsn)
 	// Open the database connection.
 	db, err := sqlx.Open("mysql", connStr)
 	if err != nil {
@@ -24,9 +29,7 @@ func ConnectDB() (*sqlx.DB, error) {
 	return db, nil
 }
 
-func getConnectionStr() string {
-	// Get the connection string from the environment.
-	connStr := os.Getenv(DbEnvVar)
+func getConnectionStr(connStr string) string {
 	// Append "?timeout=90s&multiStatements=true&parseTime=true" to the connection string. But remove any current query string.
 	if strings.Contains(connStr, "?") {
 		connStr = strings.Split(connStr, "?")[0]
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -222,6 +223,57 @@ func orderFiles(files []os.DirEntry) ([]os.DirEntry, error) {
 	return ordered, nil
 }
 
+// sortFiles returns the migration files sorted by the version of their datetime prefix, keeping the order of files
+// of the same version.
+func sortFiles(files []os.DirEntry) ([]os.DirEntry, error) {
+	versions := make(map[string]time.Time, len(files))
+	for _, f := range files {
+		// Get the datetime prefix.
+		prefix, err := getDatetimePrefix(f.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error getting datetime prefix: %w", err)
+		}
+
+		// Parse the datetime prefix.
+		parsed, err := time.Parse(FilePrefix, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing datetime prefix: %w", err)
+		}
+
+		versions[f.Name()] = parsed
+	}
+
+	ordered := make([]os.DirEntry, len(files))
+	copy(ordered, files)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return versions[ordered[i].Name()].Before(versions[ordered[j].Name()])
+	})
+
+	return ordered, nil
+}
+
+// UpFiles returns the paths of the up migrations in the given location, ordered by their version.
+func UpFiles(location string) ([]string, error) {
+	files, err := getFiles(location)
+	if err != nil {
+		return nil, fmt.Errorf("error getting files: %w", err)
+	}
+
+	files = filterFiles(files, up+".sql")
+
+	orderedFiles, err := sortFiles(files)
+	if err != nil {
+		return nil, fmt.Errorf("error ordering files: %w", err)
+	}
+
+	paths := make([]string, len(orderedFiles))
+	for i, f := range orderedFiles {
+		paths[i] = filepath.Join(location, f.Name())
+	}
+
+	return paths, nil
+}
+
 func getDatetimePrefix(name string) (string, error) {
 	// Get the datetime prefix.
 	parts := strings.Split(name, "_")
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestUpFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"20240301120000_posts.up.sql",
+		"20240101120000_init.up.sql",
+		"20240101120000_init.down.sql",
+		"20240201120000_users.up.sql",
+		"README.md",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := UpFiles(dir)
+	if err != nil {
+		t.Fatalf("UpFiles() error = %v", err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "20240101120000_init.up.sql"),
+		filepath.Join(dir, "20240201120000_users.up.sql"),
+		filepath.Join(dir, "20240301120000_posts.up.sql"),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("UpFiles() = %v, want %v", got, want)
+	}
+
+	if _, err := UpFiles(filepath.Join(dir, "README.md")); err == nil {
+		t.Error("UpFiles() of a file error = nil, want an error")
+	}
+}
@@ -1,4 +1,4 @@
 package models
 
 //go:generate rm -f ./*.xo.go
-//go:generate goschema generate --out=./ --sql=./schemas/*.sql --extension=xo
+//go:generate goschema generate --config=./goschema.yaml --out=./ --sql=./schemas/*.sql --extension=xo
@@ -0,0 +1,132 @@
+package usql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Bit represents a mysql BIT(n) column. MySQL returns bit values as big-endian binary strings.
+type Bit uint64
+
+// Bool returns true if any bit is set. This is useful for BIT(1) columns used as flags.
+func (b Bit) Bool() bool {
+	return b != 0
+}
+
+// IsSet returns true if the bit at the given position is set, where position 0 is the least significant bit.
+func (b Bit) IsSet(pos uint) bool {
+	return b&(1<<pos) != 0
+}
+
+// Scan implements the Scanner interface.
+func (b *Bit) Scan(value any) error {
+	switch v := value.(type) {
+	case []byte:
+		if len(v) > 8 {
+			return fmt.Errorf("bit value of %d bytes is too large", len(v))
+		}
+		var n uint64
+		for _, c := range v {
+			n = n<<8 | uint64(c)
+		}
+		*b = Bit(n)
+	case int64:
+		*b = Bit(v)
+	case uint64:
+		*b = Bit(v)
+	case string:
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		*b = Bit(n)
+	default:
+		return fmt.Errorf("can't convert %T to usql.Bit", value)
+	}
+
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (b Bit) Value() (driver.Value, error) {
+	if uint64(b) > math.MaxInt64 {
+		// Values that do not fit in an int64 are sent as a binary string.
+		v := make([]byte, 8)
+		for i := range v {
+			v[7-i] = byte(b >> (8 * i))
+		}
+		return v, nil
+	}
+
+	return int64(b), nil
+}
+
+// NullBit represents a nullable mysql BIT(n) column which supports json Marshaler, sql Scanner, and sql driver
+// Valuer interfaces.
+type NullBit struct {
+	Bit   Bit
+	Valid bool
+}
+
+// Val returns the Bit value of the NullBit.
+func (r NullBit) Val() Bit {
+	return r.Bit
+}
+
+// Scan implements the Scanner interface.
+func (r *NullBit) Scan(value any) error {
+	if value == nil {
+		r.Bit, r.Valid = 0, false
+		return nil
+	}
+
+	if err := r.Bit.Scan(value); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullBit) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	return r.Bit.Value()
+}
+
+// MarshalJSON implements the json.Marshaler interface for a NullBit.
+func (r NullBit) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return json.Marshal(r.Bit)
+	}
+
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a NullBit.
+func (r *NullBit) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null) {
+		r.Bit = 0
+		r.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.Bit); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// NewNullBit returns a valid new NullBit for a given Bit value.
+func NewNullBit(b Bit) *NullBit {
+	return &NullBit{Bit: b, Valid: true}
+}
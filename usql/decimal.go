@@ -0,0 +1,410 @@
+package usql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidDecimal is returned when a value can not be parsed as a decimal.
+	ErrInvalidDecimal = errors.New("invalid decimal")
+
+	// ErrDecimalOverflow is returned when a decimal does not fit the precision and scale of its column.
+	ErrDecimalOverflow = errors.New("decimal out of range")
+)
+
+const (
+	// MaxDecimalDigits is the most digits that a parsed decimal can have, matching the largest MySQL DECIMAL.
+	MaxDecimalDigits = 65
+
+	// MaxDecimalScale is the most digits after the decimal point that a parsed decimal can have, matching the
+	// largest MySQL DECIMAL.
+	MaxDecimalScale = 30
+)
+
+var bigTen = big.NewInt(10)
+
+// Decimal represents a mysql DECIMAL(p,s) column with arbitrary precision. The value is stored as an unscaled
+// integer and a scale, so 12.34 is stored as 1234 with a scale of 2. The zero value is 0.
+//
+// Decimals are marshalled to JSON as strings so that no precision is lost.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// NewDecimal returns a new Decimal of unscaled * 10^-scale, e.g. NewDecimal(1234, 2) is 12.34.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return newDecimal(big.NewInt(unscaled), scale)
+}
+
+// NewDecimalFromInt returns a new Decimal with the given integer value.
+func NewDecimalFromInt(i int64) Decimal {
+	return NewDecimal(i, 0)
+}
+
+// ParseDecimal parses a decimal from its string form, e.g. "-12.34" or "1.5e3". ErrInvalidDecimal is returned for
+// decimals with more than MaxDecimalDigits digits or MaxDecimalScale digits after the decimal point, so that
+// untrusted input can not make a decimal of any size.
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+
+	exp := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		var err error
+		exp, err = strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+		}
+		str = str[:i]
+	}
+
+	sign := ""
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		sign, str = str[:1], str[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale > MaxDecimalScale {
+		return Decimal{}, fmt.Errorf("%w: %q has more than %d digits after the decimal point", ErrInvalidDecimal, s, MaxDecimalScale)
+	}
+
+	// A negative scale appends zeros to the significant digits.
+	precision := int64(len(strings.TrimLeft(digits, "0")))
+	if precision > 0 && scale < 0 {
+		precision -= scale
+	}
+	if precision > MaxDecimalDigits {
+		return Decimal{}, fmt.Errorf("%w: %q has more than %d digits", ErrInvalidDecimal, s, MaxDecimalDigits)
+	}
+
+	unscaled, ok := new(big.Int).SetString(sign+digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+	}
+
+	if scale < 0 {
+		if unscaled.Sign() != 0 {
+			unscaled.Mul(unscaled, pow10(-scale))
+		}
+		scale = 0
+	}
+
+	return newDecimal(unscaled, int32(scale)), nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if the string can not be parsed.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// newDecimal returns a new Decimal, normalising negative scales
+func newDecimal(unscaled *big.Int, scale int32) Decimal {
+	if scale < 0 {
+		unscaled = new(big.Int).Mul(unscaled, pow10(int64(-scale)))
+		scale = 0
+	}
+
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+// int returns the unscaled value of the decimal
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return d.unscaled
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the decimal.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero returns true if the decimal is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares the decimals, returning -1 if d < e, 0 if d == e and 1 if d > e.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b, _ := align(d, e)
+	return a.Cmp(b)
+}
+
+// Equal returns true if the decimals have the same value, regardless of scale.
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns the absolute value of d.
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Add returns d + e.
+func (d Decimal) Add(e Decimal) Decimal {
+	a, b, scale := align(d, e)
+	return Decimal{unscaled: new(big.Int).Add(a, b), scale: scale}
+}
+
+// Sub returns d - e.
+func (d Decimal) Sub(e Decimal) Decimal {
+	a, b, scale := align(d, e)
+	return Decimal{unscaled: new(big.Int).Sub(a, b), scale: scale}
+}
+
+// Mul returns d * e. The scale of the result is the sum of the scales of d and e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale}
+}
+
+// Div returns d / e rounded half away from zero to the given scale. Div panics if e is 0.
+func (d Decimal) Div(e Decimal, scale int32) Decimal {
+	if e.IsZero() {
+		panic("usql: decimal division by zero")
+	}
+
+	// Divide with one extra digit so that the result can be rounded.
+	num := new(big.Int).Set(d.int())
+	den := new(big.Int).Set(e.int())
+	shift := int64(scale) + 1 + int64(e.scale) - int64(d.scale)
+	if shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+
+	return Decimal{unscaled: num.Quo(num, den), scale: scale + 1}.Round(scale)
+}
+
+// Round returns d rounded half away from zero to the given number of decimal places. If d already has the same
+// or fewer decimal places it is returned unchanged.
+func (d Decimal) Round(scale int32) Decimal {
+	if d.scale <= scale {
+		return d
+	}
+
+	divisor := pow10(int64(d.scale - scale))
+	q, r := new(big.Int).QuoRem(d.int(), divisor, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(divisor) >= 0 {
+		q.Add(q, big.NewInt(int64(d.Sign())))
+	}
+
+	return newDecimal(q, scale)
+}
+
+// Truncate returns d with any digits after the given number of decimal places removed.
+func (d Decimal) Truncate(scale int32) Decimal {
+	if d.scale <= scale {
+		return d
+	}
+
+	return newDecimal(new(big.Int).Quo(d.int(), pow10(int64(d.scale-scale))), scale)
+}
+
+// Fits returns true if the decimal can be stored in a DECIMAL(precision, scale) column. Digits after the given
+// scale are ignored as mysql rounds them.
+func (d Decimal) Fits(precision, scale int) bool {
+	r := d.Round(int32(scale))
+	intPart := new(big.Int).Abs(r.int())
+	intPart.Quo(intPart, pow10(int64(r.scale)))
+	if intPart.Sign() == 0 {
+		return true
+	}
+
+	return len(intPart.String()) <= precision-scale
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns the decimal in plain notation, e.g. "-12.34".
+func (d Decimal) String() string {
+	str := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) - len(str) + 1; pad > 0 {
+			str = strings.Repeat("0", pad) + str
+		}
+		str = str[:len(str)-int(d.scale)] + "." + str[len(str)-int(d.scale):]
+	}
+
+	if d.Sign() < 0 {
+		return "-" + str
+	}
+
+	return str
+}
+
+// Scan implements the Scanner interface.
+func (d *Decimal) Scan(value any) error {
+	var (
+		parsed Decimal
+		err    error
+	)
+	switch v := value.(type) {
+	case []byte:
+		parsed, err = ParseDecimal(string(v))
+	case string:
+		parsed, err = ParseDecimal(v)
+	case int64:
+		parsed = NewDecimalFromInt(v)
+	case float64:
+		parsed, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("can't convert %T to usql.Decimal", value)
+	}
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// Value implements the driver Valuer interface, sending the decimal as a string so that no precision is lost.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface, writing the decimal as a string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Both strings and numbers are accepted.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	str := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseDecimal(str)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// NullDecimal represents a nullable mysql DECIMAL(p,s) column which supports json Marshaler, sql Scanner, and sql
+// driver Valuer interfaces.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool
+}
+
+// Val returns the Decimal value of the NullDecimal.
+func (r NullDecimal) Val() Decimal {
+	return r.Decimal
+}
+
+// Fits returns true if the decimal is null or can be stored in a DECIMAL(precision, scale) column.
+func (r NullDecimal) Fits(precision, scale int) bool {
+	return !r.Valid || r.Decimal.Fits(precision, scale)
+}
+
+// Scan implements the Scanner interface.
+func (r *NullDecimal) Scan(value any) error {
+	if value == nil {
+		r.Decimal, r.Valid = Decimal{}, false
+		return nil
+	}
+
+	if err := r.Decimal.Scan(value); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullDecimal) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	return r.Decimal.Value()
+}
+
+// MarshalJSON implements the json.Marshaler interface for a NullDecimal.
+func (r NullDecimal) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return json.Marshal(r.Decimal)
+	}
+
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a NullDecimal.
+func (r *NullDecimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null) {
+		r.Decimal = Decimal{}
+		r.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.Decimal); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// NewNullDecimal returns a valid new NullDecimal for a given Decimal value.
+func NewNullDecimal(d Decimal) *NullDecimal {
+	return &NullDecimal{Decimal: d, Valid: true}
+}
+
+// align returns the unscaled values of the decimals at a common scale
+func align(d, e Decimal) (*big.Int, *big.Int, int32) {
+	switch {
+	case d.scale > e.scale:
+		return d.int(), new(big.Int).Mul(e.int(), pow10(int64(d.scale-e.scale))), d.scale
+	case d.scale < e.scale:
+		return new(big.Int).Mul(d.int(), pow10(int64(e.scale-d.scale))), e.int(), e.scale
+	default:
+		return d.int(), e.int(), d.scale
+	}
+}
+
+// pow10 returns 10^n
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
@@ -0,0 +1,135 @@
+package usql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON represents a mysql JSON column holding a value of type T. The value is unmarshalled when scanned and
+// marshalled when written, so callers do not need to handle the raw JSON.
+type JSON[T any] struct {
+	V T
+}
+
+// NewJSON returns a new JSON for a given value.
+func NewJSON[T any](v T) JSON[T] {
+	return JSON[T]{V: v}
+}
+
+// Val returns the value of the JSON.
+func (j JSON[T]) Val() T {
+	return j.V
+}
+
+// Scan implements the Scanner interface.
+func (j *JSON[T]) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("can't convert %T to usql.JSON", value)
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("can't unmarshal usql.JSON: %w", err)
+	}
+
+	j.V = v
+	return nil
+}
+
+// Value implements the driver Valuer interface. The JSON is sent as a string, as mysql rejects JSON values with
+// a binary character set.
+func (j JSON[T]) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for a JSON.
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.V)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a JSON.
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.V)
+}
+
+// NullJSON represents a nullable mysql JSON column holding a value of type T which supports json Marshaler, sql
+// Scanner, and sql driver Valuer interfaces. A SQL NULL is not the same as a JSON null, which is scanned as a
+// valid zero value.
+type NullJSON[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNullJSON returns a valid new NullJSON for a given value.
+func NewNullJSON[T any](v T) *NullJSON[T] {
+	return &NullJSON[T]{V: v, Valid: true}
+}
+
+// Val returns the value of the NullJSON.
+func (r NullJSON[T]) Val() T {
+	return r.V
+}
+
+// Scan implements the Scanner interface.
+func (r *NullJSON[T]) Scan(value any) error {
+	if value == nil {
+		var zero T
+		r.V, r.Valid = zero, false
+		return nil
+	}
+
+	var j JSON[T]
+	if err := j.Scan(value); err != nil {
+		return err
+	}
+	r.V, r.Valid = j.V, true
+
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullJSON[T]) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	return JSON[T]{V: r.V}.Value()
+}
+
+// MarshalJSON implements the json.Marshaler interface for a NullJSON.
+func (r NullJSON[T]) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return json.Marshal(r.V)
+	}
+
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a NullJSON.
+func (r *NullJSON[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null) {
+		var zero T
+		r.V, r.Valid = zero, false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.V); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
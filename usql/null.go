@@ -0,0 +1,93 @@
+package usql
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Null represents a nullable value of any type which supports json Marshaler, sql Scanner, and sql driver Valuer
+// interfaces. It is used for the column types that do not have a named nullable type, e.g. Null[uint32] for a
+// nullable INT UNSIGNED column.
+type Null[T any] struct {
+	sql.Null[T]
+}
+
+// Value implements the driver Valuer interface. Values are converted to one of the driver value types, so a
+// Null[uint16] is sent as an int64.
+func (r Null[T]) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	if u, ok := any(r.V).(uint64); ok && u > math.MaxInt64 {
+		// The mysql driver supports uint64 values that do not fit in an int64.
+		return u, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(r.V)
+}
+
+// MarshalJSON implements the json.Marshaler interface for a Null.
+func (r Null[T]) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return json.Marshal(r.V)
+	}
+
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a Null.
+func (r *Null[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null) {
+		var zero T
+		r.V = zero
+		r.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.V); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// Val returns the value of the Null.
+func (r Null[T]) Val() T {
+	return r.V
+}
+
+// RedisArg implements redis.Argument.
+//
+// The caller should explicitly check the Valid field when putting Null
+// values into Redis. If this is not done it can lead to confusion as RedisScan
+// on this type will treat an empty non-nil string as valid.
+func (r Null[T]) RedisArg() any {
+	return r.V
+}
+
+// RedisScan implements redis.Scanner.
+func (r *Null[T]) RedisScan(src any) error {
+	if src == nil {
+		var zero T
+		r.V, r.Valid = zero, false
+		return nil
+	}
+
+	switch src.(type) {
+	case []byte, string:
+		return r.Null.Scan(src)
+	default:
+		return fmt.Errorf("unexpected type: %T", src)
+	}
+}
+
+// NewNull returns a valid new Null for a given value.
+func NewNull[T any](v T) *Null[T] {
+	return &Null[T]{Null: sql.Null[T]{V: v, Valid: true}}
+}
@@ -0,0 +1,129 @@
+package usql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Set represents a mysql SET column, holding the members of the set.
+type Set []string
+
+// NewSet returns a Set holding the given members.
+func NewSet(members ...string) Set {
+	return members
+}
+
+// Contains returns true if the set holds the given member.
+func (s Set) Contains(member string) bool {
+	return slices.Contains(s, member)
+}
+
+// Scan implements the Scanner interface.
+func (s *Set) Scan(value any) error {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		str = string(v)
+	case string:
+		str = v
+	default:
+		return fmt.Errorf("can't convert %T to usql.Set", value)
+	}
+
+	if str == "" {
+		*s = Set{}
+		return nil
+	}
+
+	*s = strings.Split(str, ",")
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (s Set) Value() (driver.Value, error) {
+	for _, member := range s {
+		if strings.Contains(member, ",") {
+			return nil, fmt.Errorf("set member %q contains a comma", member)
+		}
+	}
+
+	return strings.Join(s, ","), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface. A set is always marshalled as an array.
+func (s Set) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal([]string(s))
+}
+
+// NullSet represents a nullable mysql SET column which supports json Marshaler, sql Scanner, and sql driver Valuer
+// interfaces.
+type NullSet struct {
+	Set   Set
+	Valid bool
+}
+
+// Val returns the Set value of the NullSet.
+func (r NullSet) Val() Set {
+	return r.Set
+}
+
+// Scan implements the Scanner interface.
+func (r *NullSet) Scan(value any) error {
+	if value == nil {
+		r.Set, r.Valid = nil, false
+		return nil
+	}
+
+	r.Valid = true
+	return r.Set.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (r NullSet) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	return r.Set.Value()
+}
+
+// MarshalJSON implements the json.Marshaler interface for a NullSet.
+func (r NullSet) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return r.Set.MarshalJSON()
+	}
+
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a NullSet.
+func (r *NullSet) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null) {
+		r.Set = nil
+		r.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.Set); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// NewNullSet returns a valid new NullSet holding the given members.
+func NewNullSet(members ...string) *NullSet {
+	return &NullSet{Set: NewSet(members...), Valid: true}
+}
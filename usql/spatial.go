@@ -0,0 +1,253 @@
+package usql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// wkbPoint is the WKB geometry type of a point.
+	wkbPoint = 1
+
+	// sridSize is the size of the SRID that prefixes the WKB in the mysql internal geometry format.
+	sridSize = 4
+
+	// wkbPointSize is the size of a WKB point: byte order, geometry type and two coordinates.
+	wkbPointSize = 1 + 4 + 8 + 8
+)
+
+var (
+	// ErrInvalidGeometry is returned when a value is not in the mysql internal geometry format.
+	ErrInvalidGeometry = errors.New("invalid geometry")
+)
+
+// Geometry represents a mysql spatial column. MySQL stores spatial values as a 4 byte SRID followed by the
+// well-known binary (WKB) representation of the geometry.
+type Geometry struct {
+	SRID uint32
+	WKB  []byte
+}
+
+// Type returns the WKB geometry type, e.g. 1 for a point.
+func (g Geometry) Type() (uint32, error) {
+	order, err := wkbByteOrder(g.WKB)
+	if err != nil {
+		return 0, err
+	}
+	if len(g.WKB) < 5 {
+		return 0, ErrInvalidGeometry
+	}
+
+	return order.Uint32(g.WKB[1:5]), nil
+}
+
+// Point returns the geometry as a point. An error is returned if the geometry is not a point.
+func (g Geometry) Point() (Point, error) {
+	order, err := wkbByteOrder(g.WKB)
+	if err != nil {
+		return Point{}, err
+	}
+	if len(g.WKB) != wkbPointSize || order.Uint32(g.WKB[1:5]) != wkbPoint {
+		return Point{}, fmt.Errorf("%w: not a point", ErrInvalidGeometry)
+	}
+
+	return Point{
+		SRID: g.SRID,
+		X:    math.Float64frombits(order.Uint64(g.WKB[5:13])),
+		Y:    math.Float64frombits(order.Uint64(g.WKB[13:21])),
+	}, nil
+}
+
+// Scan implements the Scanner interface.
+func (g *Geometry) Scan(value any) error {
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("can't convert %T to usql.Geometry", value)
+	}
+
+	if len(b) < sridSize+1 {
+		return fmt.Errorf("%w: %d bytes is too short", ErrInvalidGeometry, len(b))
+	}
+
+	g.SRID = binary.LittleEndian.Uint32(b[:sridSize])
+	g.WKB = bytes.Clone(b[sridSize:])
+	return nil
+}
+
+// Value implements the driver Valuer interface, writing the geometry in the mysql internal format.
+func (g Geometry) Value() (driver.Value, error) {
+	b := make([]byte, sridSize, sridSize+len(g.WKB))
+	binary.LittleEndian.PutUint32(b, g.SRID)
+	return append(b, g.WKB...), nil
+}
+
+// Point represents a mysql POINT column.
+type Point struct {
+	SRID uint32  `json:"srid"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// Geometry returns the point as a Geometry.
+func (p Point) Geometry() Geometry {
+	wkb := make([]byte, wkbPointSize)
+	wkb[0] = 1 // Little endian
+	binary.LittleEndian.PutUint32(wkb[1:5], wkbPoint)
+	binary.LittleEndian.PutUint64(wkb[5:13], math.Float64bits(p.X))
+	binary.LittleEndian.PutUint64(wkb[13:21], math.Float64bits(p.Y))
+	return Geometry{SRID: p.SRID, WKB: wkb}
+}
+
+// Scan implements the Scanner interface.
+func (p *Point) Scan(value any) error {
+	var g Geometry
+	if err := g.Scan(value); err != nil {
+		return err
+	}
+
+	point, err := g.Point()
+	if err != nil {
+		return err
+	}
+
+	*p = point
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (p Point) Value() (driver.Value, error) {
+	return p.Geometry().Value()
+}
+
+// NullGeometry represents a nullable mysql spatial column which supports sql Scanner and sql driver Valuer
+// interfaces.
+type NullGeometry struct {
+	Geometry Geometry
+	Valid    bool
+}
+
+// Val returns the Geometry value of the NullGeometry.
+func (r NullGeometry) Val() Geometry {
+	return r.Geometry
+}
+
+// Scan implements the Scanner interface.
+func (r *NullGeometry) Scan(value any) error {
+	if value == nil {
+		r.Geometry, r.Valid = Geometry{}, false
+		return nil
+	}
+
+	if err := r.Geometry.Scan(value); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullGeometry) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	return r.Geometry.Value()
+}
+
+// NewNullGeometry returns a valid new NullGeometry for a given Geometry value.
+func NewNullGeometry(g Geometry) *NullGeometry {
+	return &NullGeometry{Geometry: g, Valid: true}
+}
+
+// NullPoint represents a nullable mysql POINT column which supports json Marshaler, sql Scanner, and sql driver
+// Valuer interfaces.
+type NullPoint struct {
+	Point Point
+	Valid bool
+}
+
+// Val returns the Point value of the NullPoint.
+func (r NullPoint) Val() Point {
+	return r.Point
+}
+
+// Scan implements the Scanner interface.
+func (r *NullPoint) Scan(value any) error {
+	if value == nil {
+		r.Point, r.Valid = Point{}, false
+		return nil
+	}
+
+	if err := r.Point.Scan(value); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullPoint) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	return r.Point.Value()
+}
+
+// MarshalJSON implements the json.Marshaler interface for a NullPoint.
+func (r NullPoint) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return json.Marshal(r.Point)
+	}
+
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a NullPoint.
+func (r *NullPoint) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null) {
+		r.Point = Point{}
+		r.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.Point); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// NewNullPoint returns a valid new NullPoint for a given Point value.
+func NewNullPoint(p Point) *NullPoint {
+	return &NullPoint{Point: p, Valid: true}
+}
+
+// wkbByteOrder returns the byte order of the given WKB
+func wkbByteOrder(wkb []byte) (binary.ByteOrder, error) {
+	if len(wkb) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidGeometry)
+	}
+
+	switch wkb[0] {
+	case 0:
+		return binary.BigEndian, nil
+	case 1:
+		return binary.LittleEndian, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown byte order %d", ErrInvalidGeometry, wkb[0])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"math"
 	"strconv"
 	"strings"
 	"testing"
@@ -578,6 +579,258 @@ func (s *TypesSuite) TestValidNullTimeRedisScan_invalidType() {
 	s.Equal(time.Time{}, ts.Field1.Time)
 }
 
+func (s *TypesSuite) TestSetScan() {
+	var set Set
+	s.Require().NoError(set.Scan([]byte("news,sport")))
+	s.Equal(Set{"news", "sport"}, set)
+	s.True(set.Contains("sport"))
+
+	s.Require().NoError(set.Scan(""))
+	s.Equal(Set{}, set)
+}
+
+func (s *TypesSuite) TestSetValue() {
+	v, err := NewSet("news", "sport").Value()
+	s.Require().NoError(err)
+	s.Equal("news,sport", v)
+
+	_, err = NewSet("a,b").Value()
+	s.Require().Error(err)
+}
+
+func (s *TypesSuite) TestNullSetScan() {
+	var set NullSet
+	s.Require().NoError(set.Scan(nil))
+	s.False(set.Valid)
+
+	s.Require().NoError(set.Scan([]byte("")))
+	s.True(set.Valid)
+	s.Empty(set.Set)
+}
+
+func (s *TypesSuite) TestBitScan() {
+	var b Bit
+	s.Require().NoError(b.Scan([]byte{0x01, 0x02}))
+	s.Equal(Bit(0x0102), b)
+	s.True(b.IsSet(1))
+	s.False(b.IsSet(0))
+
+	s.Require().NoError(b.Scan([]byte{0x00}))
+	s.False(b.Bool())
+}
+
+func (s *TypesSuite) TestUUIDValue() {
+	u, err := ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	s.Require().NoError(err)
+
+	v, err := u.Value()
+	s.Require().NoError(err)
+	s.Len(v, 16)
+
+	var scanned UUID
+	s.Require().NoError(scanned.Scan(v))
+	s.Equal(u, scanned)
+
+	j, err := encodeJSON(u)
+	s.Require().NoError(err)
+	s.Equal(`"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`, j)
+}
+
+func (s *TypesSuite) TestPointScan() {
+	p := Point{SRID: 4326, X: 1.5, Y: -2.25}
+	v, err := p.Value()
+	s.Require().NoError(err)
+
+	var scanned Point
+	s.Require().NoError(scanned.Scan(v))
+	s.Equal(p, scanned)
+
+	var g Geometry
+	s.Require().NoError(g.Scan(v))
+	typ, err := g.Type()
+	s.Require().NoError(err)
+	s.Equal(uint32(1), typ)
+}
+
+func (s *TypesSuite) TestInvalidPointScan() {
+	var p Point
+	s.Require().ErrorIs(p.Scan([]byte{0x00}), ErrInvalidGeometry)
+}
+
+func (s *TypesSuite) TestParseDecimal() {
+	tests := map[string]string{
+		"12.34":       "12.34",
+		"-0.05":       "-0.05",
+		"+7":          "7",
+		"1.5e3":       "1500",
+		"1.25E-1":     "0.125",
+		".5":          "0.5",
+		"0e999999999": "0",
+		"1e64":        "1" + strings.Repeat("0", 64),
+		"1e-30":       "0." + strings.Repeat("0", 29) + "1",
+		"-" + strings.Repeat("9", 35) + "." + strings.Repeat("9", 30): "-" + strings.Repeat("9", 35) + "." + strings.Repeat("9", 30),
+	}
+	for in, want := range tests {
+		d, err := ParseDecimal(in)
+		s.Require().NoError(err, in)
+		s.Equal(want, d.String(), in)
+	}
+
+	invalid := []string{
+		"", ".", "1.2.3", "abc", "1e",
+		"1e999999999", "1e-999999999", "1e-2147483648", "1e2147483648", "1e65", "1e-31",
+		strings.Repeat("9", 66), "0." + strings.Repeat("1", 31),
+	}
+	for _, in := range invalid {
+		_, err := ParseDecimal(in)
+		s.Require().ErrorIs(err, ErrInvalidDecimal, in)
+	}
+}
+
+func (s *TypesSuite) TestDecimalArithmetic() {
+	a := MustParseDecimal("10.25")
+	b := MustParseDecimal("0.1")
+
+	s.Equal("10.35", a.Add(b).String())
+	s.Equal("10.15", a.Sub(b).String())
+	s.Equal("1.025", a.Mul(b).String())
+	s.Equal("3.42", a.Div(NewDecimalFromInt(3), 2).String())
+	s.Equal("-3.42", a.Neg().Div(NewDecimalFromInt(3), 2).String())
+	s.True(MustParseDecimal("0.30").Equal(b.Add(MustParseDecimal("0.2"))))
+	s.Equal(1, a.Cmp(b))
+}
+
+func (s *TypesSuite) TestDecimalRound() {
+	s.Equal("1.13", MustParseDecimal("1.125").Round(2).String())
+	s.Equal("-1.13", MustParseDecimal("-1.125").Round(2).String())
+	s.Equal("1.12", MustParseDecimal("1.125").Truncate(2).String())
+	s.Equal("1.1", MustParseDecimal("1.1").Round(2).String())
+}
+
+func (s *TypesSuite) TestDecimalFits() {
+	s.True(MustParseDecimal("99999999.99").Fits(10, 2))
+	s.True(MustParseDecimal("12.345").Fits(4, 2))
+	s.False(MustParseDecimal("99.995").Fits(4, 2))
+	s.False(MustParseDecimal("100").Fits(4, 2))
+	s.True(NullDecimal{}.Fits(1, 0))
+}
+
+func (s *TypesSuite) TestDecimalScan() {
+	var d Decimal
+	s.Require().NoError(d.Scan([]byte("1234.50")))
+	s.Equal("1234.50", d.String())
+
+	v, err := d.Value()
+	s.Require().NoError(err)
+	s.Equal("1234.50", v)
+
+	var nd NullDecimal
+	s.Require().NoError(nd.Scan(nil))
+	s.False(nd.Valid)
+}
+
+func (s *TypesSuite) TestDecimalJSON() {
+	j, err := encodeJSON(MustParseDecimal("0.10"))
+	s.Require().NoError(err)
+	s.Equal(`"0.10"`, j)
+
+	var d Decimal
+	s.Require().NoError(json.Unmarshal([]byte(`12.5`), &d))
+	s.Equal("12.5", d.String())
+	s.Require().ErrorIs(json.Unmarshal([]byte(`"1e-999999999"`), &d), ErrInvalidDecimal)
+
+	var nd NullDecimal
+	s.Require().NoError(json.Unmarshal([]byte(`"3.14"`), &nd))
+	s.True(nd.Valid)
+	s.Equal("3.14", nd.Val().String())
+
+	j, err = encodeJSON(NullDecimal{})
+	s.Require().NoError(err)
+	s.Equal("null", j)
+}
+
+func (s *TypesSuite) TestNullValue() {
+	v, err := NewNull[uint16](7).Value()
+	s.Require().NoError(err)
+	s.Equal(int64(7), v)
+
+	v, err = NewNull[uint64](math.MaxUint64).Value()
+	s.Require().NoError(err)
+	s.Equal(uint64(math.MaxUint64), v)
+
+	v, err = Null[int8]{}.Value()
+	s.Require().NoError(err)
+	s.Nil(v)
+}
+
+func (s *TypesSuite) TestNullScan() {
+	var n Null[uint32]
+	s.Require().NoError(n.Scan(int64(42)))
+	s.True(n.Valid)
+	s.Equal(uint32(42), n.Val())
+
+	s.Require().NoError(n.Scan(nil))
+	s.False(n.Valid)
+
+	s.Require().NoError(n.RedisScan([]byte("12")))
+	s.True(n.Valid)
+	s.Equal(uint32(12), n.Val())
+
+	s.Require().Error(n.RedisScan(1.5))
+}
+
+func (s *TypesSuite) TestNullJSON() {
+	j, err := encodeJSON(NewNull[int16](-3))
+	s.Require().NoError(err)
+	s.Equal("-3", j)
+
+	j, err = encodeJSON(Null[int16]{})
+	s.Require().NoError(err)
+	s.Equal("null", j)
+
+	var n Null[uint8]
+	s.Require().NoError(json.Unmarshal([]byte("200"), &n))
+	s.True(n.Valid)
+	s.Equal(uint8(200), n.Val())
+
+	s.Require().NoError(json.Unmarshal(null, &n))
+	s.False(n.Valid)
+}
+
+func (s *TypesSuite) TestJSONScan() {
+	type settings struct {
+		Theme string `json:"theme"`
+	}
+
+	var j JSON[settings]
+	s.Require().NoError(j.Scan([]byte(`{"theme":"dark"}`)))
+	s.Equal("dark", j.Val().Theme)
+
+	v, err := j.Value()
+	s.Require().NoError(err)
+	s.Equal(`{"theme":"dark"}`, v)
+
+	s.Require().Error(j.Scan(int64(1)))
+}
+
+func (s *TypesSuite) TestNullJSONScan() {
+	var n NullJSON[[]string]
+	s.Require().NoError(n.Scan(nil))
+	s.False(n.Valid)
+
+	v, err := n.Value()
+	s.Require().NoError(err)
+	s.Nil(v)
+
+	s.Require().NoError(n.Scan(`["a","b"]`))
+	s.True(n.Valid)
+	s.Equal([]string{"a", "b"}, n.Val())
+
+	j, err := encodeJSON(n)
+	s.Require().NoError(err)
+	s.Equal(`["a","b"]`, j)
+}
+
 func TestTypes(t *testing.T) {
 	suite.Run(t, &TypesSuite{})
 }
@@ -0,0 +1,102 @@
+package usql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// UUID represents a UUID stored in a mysql BINARY(16) column. It is marshalled to JSON in the standard string
+// form.
+type UUID struct {
+	uuid.UUID
+}
+
+// NewUUID returns a new random (version 4) UUID.
+func NewUUID() UUID {
+	return UUID{UUID: uuid.New()}
+}
+
+// ParseUUID parses a UUID from its string form.
+func ParseUUID(s string) (UUID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return UUID{}, err
+	}
+
+	return UUID{UUID: u}, nil
+}
+
+// Value implements the driver Valuer interface, writing the UUID as 16 bytes.
+func (u UUID) Value() (driver.Value, error) {
+	return u.UUID[:], nil
+}
+
+// NullUUID represents a nullable UUID stored in a mysql BINARY(16) column which supports json Marshaler, sql
+// Scanner, and sql driver Valuer interfaces.
+type NullUUID struct {
+	UUID  UUID
+	Valid bool
+}
+
+// Val returns the UUID value of the NullUUID.
+func (r NullUUID) Val() UUID {
+	return r.UUID
+}
+
+// Scan implements the Scanner interface.
+func (r *NullUUID) Scan(value any) error {
+	if value == nil {
+		r.UUID, r.Valid = UUID{}, false
+		return nil
+	}
+
+	if err := r.UUID.Scan(value); err != nil {
+		return fmt.Errorf("can't convert %T to usql.UUID: %w", value, err)
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// Value implements the driver Valuer interface.
+func (r NullUUID) Value() (driver.Value, error) {
+	if !r.Valid {
+		return nil, nil
+	}
+
+	return r.UUID.Value()
+}
+
+// MarshalJSON implements the json.Marshaler interface for a NullUUID.
+func (r NullUUID) MarshalJSON() ([]byte, error) {
+	if r.Valid {
+		return json.Marshal(r.UUID)
+	}
+
+	return json.Marshal(nil)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for a NullUUID.
+func (r *NullUUID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, null) {
+		r.UUID = UUID{}
+		r.Valid = false
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &r.UUID); err != nil {
+		return err
+	}
+	r.Valid = true
+
+	return nil
+}
+
+// NewNullUUID returns a valid new NullUUID for a given UUID value.
+func NewNullUUID(u UUID) *NullUUID {
+	return &NullUUID{UUID: u, Valid: true}
+}